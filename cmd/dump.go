@@ -0,0 +1,781 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pff "pff/pkg"
+)
+
+// dumpUsage contains the structures which can be dumped.
+const dumpUsage = `Usage: pff dump <structure> <file> [identifier]
+
+Structures:
+  nbt [offset]               node b-tree page (defaults to the root page)
+  bbt [offset]               block b-tree page (defaults to the root page)
+  node <identifier>          node b-tree entry
+  block <identifier>         block (raw, decrypted and trailer), internal blocks are decoded as data tree or local descriptors
+  local-descriptors <identifier>  local descriptors (subnode tree) of a node
+  heap <identifier>          heap-on-node allocations of a node
+  pc <identifier>            property context records of a node
+  tc <identifier>            table context columns and rows of a node
+
+Identifiers and offsets may be decimal or hexadecimal (0x prefix).
+Heaps, property contexts and table contexts are read from the first data block only.`
+
+// dumper represents the state of the dump command.
+type dumper struct {
+	PFF            pff.PFF
+	FormatType     string
+	EncryptionType string
+}
+
+// newDumper is a constructor for the dump command.
+func newDumper(filePath string) (*dumper, error) {
+	pst := pff.New(filePath)
+
+	header, err := pst.GetHeader()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !pst.IsValidSignature(header) {
+		return nil, errors.New("invalid Personal Folder File signature")
+	}
+
+	formatType, err := pst.GetFormatType(header)
+
+	if err != nil {
+		return nil, err
+	}
+
+	encryptionType, err := pst.GetEncryptionType(formatType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &dumper{
+		PFF:            pst,
+		FormatType:     formatType,
+		EncryptionType: encryptionType,
+	}, nil
+}
+
+// printHexDump prints the data as hexadecimal and ASCII, annotated with the (file) offset.
+func printHexDump(data []byte, offset int) {
+	for i := 0; i < len(data); i += 16 {
+		line := data[i:]
+
+		if len(line) > 16 {
+			line = line[:16]
+		}
+
+		var hexadecimal strings.Builder
+		var ascii strings.Builder
+
+		for j := 0; j < 16; j++ {
+			if j == 8 {
+				hexadecimal.WriteString(" ")
+			}
+
+			if j >= len(line) {
+				hexadecimal.WriteString("   ")
+
+				continue
+			}
+
+			hexadecimal.WriteString(fmt.Sprintf("%02x ", line[j]))
+
+			if line[j] >= 0x20 && line[j] < 0x7f {
+				ascii.WriteByte(line[j])
+			} else {
+				ascii.WriteByte('.')
+			}
+		}
+
+		fmt.Printf("%08x  %s |%s|\n", offset+i, hexadecimal.String(), ascii.String())
+	}
+}
+
+// getIdentifierSize returns the size of identifiers (NID/BID) in b-tree entries.
+func (dumper *dumper) getIdentifierSize() int {
+	if dumper.FormatType == pff.FormatType32 {
+		return 4
+	}
+
+	return 8
+}
+
+// readIdentifier reads an identifier of the format type's size.
+func (dumper *dumper) readIdentifier(data []byte) int {
+	if dumper.FormatType == pff.FormatType32 {
+		return int(binary.LittleEndian.Uint32(data[:4]))
+	}
+
+	return int(binary.LittleEndian.Uint64(data[:8]))
+}
+
+// dumpBTreePage prints the b-tree page at the offset with its decoded entries.
+func (dumper *dumper) dumpBTreePage(btreeNode pff.BTreeNode) error {
+	pageSize := 512
+
+	if dumper.FormatType == pff.FormatType64With4k {
+		pageSize = 4096
+	}
+
+	page, err := dumper.PFF.Read(pageSize, btreeNode.StartOffset)
+
+	if err != nil {
+		return err
+	}
+
+	entryCount, err := dumper.PFF.GetBTreeNodeEntryCount(dumper.FormatType, btreeNode)
+
+	if err != nil {
+		return err
+	}
+
+	maxEntryCount, err := dumper.PFF.GetBTreeNodeMaxEntryCount(dumper.FormatType, btreeNode)
+
+	if err != nil {
+		return err
+	}
+
+	entrySize, err := dumper.PFF.GetBTreeNodeEntrySize(dumper.FormatType, btreeNode)
+
+	if err != nil {
+		return err
+	}
+
+	level, err := dumper.PFF.GetBTreeNodeLevel(dumper.FormatType, btreeNode)
+
+	if err != nil {
+		return err
+	}
+
+	pageType, err := dumper.PFF.GetBTreeNodePageType(dumper.FormatType, btreeNode)
+
+	if err != nil {
+		return err
+	}
+
+	printHexDump(page, btreeNode.StartOffset)
+
+	fmt.Printf("\nB-tree page at offset %d:\n", btreeNode.StartOffset)
+	fmt.Printf("\tPage type:\t\t0x%02x", pageType)
+
+	if pageType == 0x80 {
+		fmt.Printf(" (block b-tree)\n")
+	} else if pageType == 0x81 {
+		fmt.Printf(" (node b-tree)\n")
+	} else {
+		fmt.Printf(" (unknown)\n")
+	}
+
+	fmt.Printf("\tEntry count:\t\t%d (maximum %d)\n", entryCount, maxEntryCount)
+	fmt.Printf("\tEntry size:\t\t%d\n", entrySize)
+	fmt.Printf("\tLevel:\t\t\t%d\n\n", level)
+
+	// The page header is printed first since corrupt pages may contain an invalid entry count or size.
+	entries, err := dumper.PFF.GetBTreeNodeEntries(dumper.FormatType, btreeNode)
+
+	if err != nil {
+		return err
+	}
+
+	identifierSize := dumper.getIdentifierSize()
+
+	for i, entry := range entries {
+		if level > 0 {
+			childOffset, err := dumper.PFF.GetBTreeBranchNodeEntryOffset(dumper.FormatType, entry.Data)
+
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\tEntry %d: key 0x%x, child block 0x%x, child offset %d\n", i, dumper.readIdentifier(entry.Data), dumper.readIdentifier(entry.Data[identifierSize:]), childOffset)
+		} else if pageType == 0x81 {
+			dataIdentifier, err := entry.GetDataIdentifier(dumper.FormatType)
+
+			if err != nil {
+				return err
+			}
+
+			localDescriptorsIdentifier, err := entry.GetLocalDescriptorsIdentifier(dumper.FormatType)
+
+			if err != nil {
+				return err
+			}
+
+			parentIdentifier := int(binary.LittleEndian.Uint32(entry.Data[identifierSize*3 : identifierSize*3+4]))
+
+			fmt.Printf("\tEntry %d: node 0x%x, data 0x%x, local descriptors 0x%x, parent 0x%x\n", i, dumper.readIdentifier(entry.Data), dataIdentifier, localDescriptorsIdentifier, parentIdentifier)
+		} else {
+			fileOffset, err := entry.GetFileOffset(dumper.FormatType)
+
+			if err != nil {
+				return err
+			}
+
+			size, err := entry.GetSize(dumper.FormatType)
+
+			if err != nil {
+				return err
+			}
+
+			referenceCount := int(binary.LittleEndian.Uint16(entry.Data[identifierSize*2+2 : identifierSize*2+4]))
+
+			fmt.Printf("\tEntry %d: block 0x%x, offset %d, size %d, reference count %d\n", i, dumper.readIdentifier(entry.Data), fileOffset, size, referenceCount)
+		}
+	}
+
+	return nil
+}
+
+// findNode returns the node b-tree entry of the node identifier.
+func (dumper *dumper) findNode(identifier int) (pff.BTreeNodeEntry, error) {
+	nodeBTreeEntry, err := dumper.PFF.FindNodeBTreeEntry(dumper.FormatType, identifier)
+
+	if err != nil {
+		return pff.BTreeNodeEntry{}, fmt.Errorf("node 0x%x: %s", identifier, err)
+	}
+
+	return nodeBTreeEntry, nil
+}
+
+// findBlock returns the block b-tree entry of the block identifier.
+func (dumper *dumper) findBlock(identifier int) (pff.BTreeNodeEntry, error) {
+	blockBTreeEntry, err := dumper.PFF.FindBlockBTreeEntry(dumper.FormatType, identifier)
+
+	if err != nil {
+		return pff.BTreeNodeEntry{}, fmt.Errorf("block 0x%x: %s", identifier, err)
+	}
+
+	return blockBTreeEntry, nil
+}
+
+// dumpNode prints the node b-tree entry.
+func (dumper *dumper) dumpNode(identifier int) error {
+	nodeBTreeEntry, err := dumper.findNode(identifier)
+
+	if err != nil {
+		return err
+	}
+
+	dataIdentifier, err := nodeBTreeEntry.GetDataIdentifier(dumper.FormatType)
+
+	if err != nil {
+		return err
+	}
+
+	localDescriptorsIdentifier, err := nodeBTreeEntry.GetLocalDescriptorsIdentifier(dumper.FormatType)
+
+	if err != nil {
+		return err
+	}
+
+	printHexDump(nodeBTreeEntry.Data, 0)
+
+	fmt.Printf("\nNode 0x%x:\n", identifier)
+	fmt.Printf("\tType:\t\t\t0x%02x\n", identifier&0x1f)
+	fmt.Printf("\tIndex:\t\t\t%d\n", identifier>>5)
+	fmt.Printf("\tData:\t\t\t0x%x\n", dataIdentifier)
+	fmt.Printf("\tLocal descriptors:\t0x%x\n", localDescriptorsIdentifier)
+
+	return nil
+}
+
+// dumpBlock prints the block with its trailer, internal blocks are decoded.
+func (dumper *dumper) dumpBlock(identifier int) error {
+	blockBTreeEntry, err := dumper.findBlock(identifier)
+
+	if err != nil {
+		return err
+	}
+
+	fileOffset, err := blockBTreeEntry.GetFileOffset(dumper.FormatType)
+
+	if err != nil {
+		return err
+	}
+
+	size, err := blockBTreeEntry.GetSize(dumper.FormatType)
+
+	if err != nil {
+		return err
+	}
+
+	rawData, err := dumper.PFF.Read(size, fileOffset)
+
+	if err != nil {
+		return err
+	}
+
+	isInternal := pff.IsInternalBlock(blockBTreeEntry.Identifier)
+
+	fmt.Printf("Block 0x%x:\n", blockBTreeEntry.Identifier)
+	fmt.Printf("\tOffset:\t\t\t%d\n", fileOffset)
+	fmt.Printf("\tSize:\t\t\t%d\n", size)
+	fmt.Printf("\tInternal:\t\t%t\n", isInternal)
+
+	fmt.Printf("\nRaw data:\n")
+	printHexDump(rawData, fileOffset)
+
+	if !isInternal && dumper.EncryptionType != pff.EncryptionTypeNone {
+		data, err := dumper.PFF.GetBlockData(dumper.FormatType, dumper.EncryptionType, blockBTreeEntry)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\nDecrypted data (%s):\n", dumper.EncryptionType)
+		printHexDump(data, fileOffset)
+	}
+
+	blockTrailer, err := dumper.PFF.GetBlockTrailer(dumper.FormatType, blockBTreeEntry)
+
+	if err != nil {
+		fmt.Printf("\nBlock trailer: %s\n", err)
+	} else {
+		expectedSignature := pff.GetBlockSignature(fileOffset, blockBTreeEntry.Identifier)
+		expectedCRC := pff.GetBlockCRC(rawData)
+
+		fmt.Printf("\nBlock trailer:\n")
+		fmt.Printf("\tSize:\t\t\t%d\n", blockTrailer.Size)
+		fmt.Printf("\tSignature:\t\t0x%04x (expected 0x%04x)\n", blockTrailer.Signature, expectedSignature)
+		fmt.Printf("\tCRC:\t\t\t0x%08x (expected 0x%08x)\n", blockTrailer.CRC, expectedCRC)
+		fmt.Printf("\tIdentifier:\t\t0x%x\n", blockTrailer.Identifier)
+	}
+
+	if !isInternal || len(rawData) < 2 {
+		return nil
+	}
+
+	if rawData[0] == 0x01 {
+		return dumper.printDataTree(rawData)
+	} else if rawData[0] == 0x02 {
+		return dumper.dumpLocalDescriptors(pff.NewLocalDescriptors(fileOffset))
+	}
+
+	return nil
+}
+
+// printDataTree prints the data tree (XBLOCK or XXBLOCK) entries.
+//
+// References "[MS-PST] 2.2.2.8.3.1 XBLOCKs" and "[MS-PST] 2.2.2.8.3.2 XXBLOCKs".
+func (dumper *dumper) printDataTree(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("data tree is too small")
+	}
+
+	level := int(data[1])
+	entryCount := int(binary.LittleEndian.Uint16(data[2:4]))
+	totalSize := int(binary.LittleEndian.Uint32(data[4:8]))
+	identifierSize := dumper.getIdentifierSize()
+
+	if 8+entryCount*identifierSize > len(data) {
+		return errors.New("invalid data tree entry count")
+	}
+
+	fmt.Printf("\nData tree:\n")
+	fmt.Printf("\tLevel:\t\t\t%d\n", level)
+	fmt.Printf("\tEntry count:\t\t%d\n", entryCount)
+	fmt.Printf("\tTotal size:\t\t%d\n\n", totalSize)
+
+	for i := 0; i < entryCount; i++ {
+		fmt.Printf("\tEntry %d: block 0x%x\n", i, dumper.readIdentifier(data[8+i*identifierSize:]))
+	}
+
+	return nil
+}
+
+// dumpLocalDescriptors prints the local descriptors entries, branch entries are followed.
+// The level of a branch's child local descriptors must be lower than the level of the branch.
+func (dumper *dumper) dumpLocalDescriptors(localDescriptors pff.LocalDescriptors) error {
+	signature, err := dumper.PFF.GetLocalDescriptorsSignature(localDescriptors)
+
+	if err != nil {
+		return err
+	}
+
+	if signature != 2 {
+		return errors.New("invalid local descriptors signature")
+	}
+
+	level, err := dumper.PFF.GetLocalDescriptorsNodeLevel(localDescriptors)
+
+	if err != nil {
+		return err
+	}
+
+	entries, err := dumper.PFF.GetLocalDescriptorsEntries(dumper.FormatType, localDescriptors)
+
+	if err != nil {
+		return err
+	}
+
+	identifierSize := dumper.getIdentifierSize()
+	entrySize := identifierSize * 3
+
+	if level > 0 {
+		entrySize = identifierSize * 2
+	}
+
+	fmt.Printf("\nLocal descriptors at offset %d (level %d):\n", localDescriptors.StartOffset, level)
+
+	var childIdentifiers []int
+
+	for i := 0; i*entrySize < len(entries); i++ {
+		entry := entries[i*entrySize : (i+1)*entrySize]
+
+		nodeIdentifier := dumper.readIdentifier(entry)
+
+		if level > 0 {
+			childIdentifier := dumper.readIdentifier(entry[identifierSize:])
+			childIdentifiers = append(childIdentifiers, childIdentifier)
+
+			fmt.Printf("\tEntry %d: key 0x%x, local descriptors 0x%x\n", i, nodeIdentifier, childIdentifier)
+		} else {
+			fmt.Printf("\tEntry %d: node 0x%x, data 0x%x, local descriptors 0x%x\n", i, nodeIdentifier, dumper.readIdentifier(entry[identifierSize:]), dumper.readIdentifier(entry[identifierSize*2:]))
+		}
+	}
+
+	for _, childIdentifier := range childIdentifiers {
+		childBlockBTreeEntry, err := dumper.findBlock(childIdentifier)
+
+		if err != nil {
+			return err
+		}
+
+		childFileOffset, err := childBlockBTreeEntry.GetFileOffset(dumper.FormatType)
+
+		if err != nil {
+			return err
+		}
+
+		childLocalDescriptors := pff.NewLocalDescriptors(childFileOffset)
+
+		childLevel, err := dumper.PFF.GetLocalDescriptorsNodeLevel(childLocalDescriptors)
+
+		if err != nil {
+			return err
+		}
+
+		if childLevel >= level {
+			return fmt.Errorf("invalid level %d of child local descriptors 0x%x", childLevel, childIdentifier)
+		}
+
+		if err := dumper.dumpLocalDescriptors(childLocalDescriptors); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dumpNodeLocalDescriptors prints the local descriptors of the node.
+func (dumper *dumper) dumpNodeLocalDescriptors(identifier int) error {
+	nodeBTreeEntry, err := dumper.findNode(identifier)
+
+	if err != nil {
+		return err
+	}
+
+	localDescriptorsIdentifier, err := nodeBTreeEntry.GetLocalDescriptorsIdentifier(dumper.FormatType)
+
+	if err != nil {
+		return err
+	}
+
+	if localDescriptorsIdentifier == 0 {
+		return fmt.Errorf("node 0x%x has no local descriptors", identifier)
+	}
+
+	blockBTreeEntry, err := dumper.findBlock(localDescriptorsIdentifier)
+
+	if err != nil {
+		return err
+	}
+
+	fileOffset, err := blockBTreeEntry.GetFileOffset(dumper.FormatType)
+
+	if err != nil {
+		return err
+	}
+
+	return dumper.dumpLocalDescriptors(pff.NewLocalDescriptors(fileOffset))
+}
+
+// getNodeData returns the (decrypted) data of the node.
+func (dumper *dumper) getNodeData(identifier int) ([]byte, error) {
+	data, err := dumper.PFF.GetNodeData(dumper.FormatType, identifier)
+
+	if err != nil {
+		return nil, fmt.Errorf("node 0x%x: %s", identifier, err)
+	}
+
+	return data, nil
+}
+
+// dumpHeap prints the heap-on-node header and allocations of the node.
+func (dumper *dumper) dumpHeap(identifier int) error {
+	data, err := dumper.getNodeData(identifier)
+
+	if err != nil {
+		return err
+	}
+
+	heapOnNodeHeader, err := pff.GetHeapOnNodeHeader(data)
+
+	if err != nil {
+		return err
+	}
+
+	allocations, err := pff.GetHeapOnNodeAllocations(data, heapOnNodeHeader.PageMapOffset)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Heap-on-node of node 0x%x:\n", identifier)
+	fmt.Printf("\tPage map offset:\t%d\n", heapOnNodeHeader.PageMapOffset)
+	fmt.Printf("\tSignature:\t\t0x%02x\n", heapOnNodeHeader.Signature)
+	fmt.Printf("\tClient signature:\t0x%02x\n", heapOnNodeHeader.ClientSignature)
+	fmt.Printf("\tUser root:\t\t0x%x\n", heapOnNodeHeader.UserRootIdentifier)
+	fmt.Printf("\tAllocations:\t\t%d\n", len(allocations))
+
+	for _, allocation := range allocations {
+		fmt.Printf("\nAllocation 0x%x (offset %d, size %d):\n", allocation.Identifier, allocation.Offset, allocation.Size)
+		printHexDump(data[allocation.Offset:allocation.Offset+allocation.Size], allocation.Offset)
+	}
+
+	if heapOnNodeHeader.ClientSignature == pff.HeapOnNodeClientSignatureProperties || heapOnNodeHeader.ClientSignature == pff.HeapOnNodeClientSignatureBTree {
+		btreeOnHeapHeader, err := pff.GetBTreeOnHeapHeader(data, heapOnNodeHeader.UserRootIdentifier)
+
+		if err != nil {
+			return err
+		}
+
+		btreeOnHeapRecords, err := pff.GetBTreeOnHeapRecords(data, btreeOnHeapHeader)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\nB-tree-on-heap:\n")
+		fmt.Printf("\tKey size:\t\t%d\n", btreeOnHeapHeader.KeySize)
+		fmt.Printf("\tEntry size:\t\t%d\n", btreeOnHeapHeader.EntrySize)
+		fmt.Printf("\tIndex levels:\t\t%d\n", btreeOnHeapHeader.IndexLevels)
+		fmt.Printf("\tRoot:\t\t\t0x%x\n", btreeOnHeapHeader.RootIdentifier)
+		fmt.Printf("\tRecords:\t\t%d\n\n", len(btreeOnHeapRecords))
+
+		for i, btreeOnHeapRecord := range btreeOnHeapRecords {
+			fmt.Printf("\tRecord %d: key %x, data %x\n", i, btreeOnHeapRecord.Key, btreeOnHeapRecord.Data)
+		}
+	}
+
+	return nil
+}
+
+// dumpPropertyContext prints the property context records of the node.
+//
+// References "[MS-PST] 2.3.3.3 PC BTH Record":
+// Values of fixed size types up to 4 bytes are stored in the record, other values are referenced by a HNID.
+func (dumper *dumper) dumpPropertyContext(identifier int) error {
+	data, err := dumper.getNodeData(identifier)
+
+	if err != nil {
+		return err
+	}
+
+	propertyContextRecords, err := pff.GetPropertyContextRecords(data)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Property context of node 0x%x (%d properties):\n", identifier, len(propertyContextRecords))
+
+	for _, propertyContextRecord := range propertyContextRecords {
+		fmt.Printf("\nProperty 0x%04x, type 0x%04x: ", propertyContextRecord.PropertyID, propertyContextRecord.PropertyType)
+
+		switch propertyContextRecord.PropertyType {
+		case 0x0002, 0x0003, 0x0004, 0x000a, 0x000b:
+			fmt.Printf("value 0x%08x (%d)\n", propertyContextRecord.Value, propertyContextRecord.Value)
+		default:
+			printHeapValue(data, propertyContextRecord.Value)
+		}
+	}
+
+	return nil
+}
+
+// printHeapValue prints the value referenced by the heap identifier or local descriptor identifier (HNID).
+func printHeapValue(data []byte, heapIdentifier int) {
+	if heapIdentifier == 0 {
+		fmt.Printf("empty\n")
+	} else if heapIdentifier&0x1f != 0 {
+		fmt.Printf("local descriptor node 0x%x\n", heapIdentifier)
+	} else {
+		value, err := pff.GetHeapOnNodeAllocation(data, heapIdentifier)
+
+		if err != nil {
+			fmt.Printf("heap 0x%x (%s)\n", heapIdentifier, err)
+
+			return
+		}
+
+		fmt.Printf("heap 0x%x, %d bytes\n", heapIdentifier, len(value))
+		printHexDump(value, 0)
+	}
+}
+
+// tableContextFixedPropertyTypes contains the property types of which the value is stored in the row.
+// Values of other property types are referenced by a HNID.
+var tableContextFixedPropertyTypes = map[int]bool{
+	0x0002: true, // PT_I2
+	0x0003: true, // PT_LONG
+	0x0004: true, // PT_FLOAT
+	0x0005: true, // PT_DOUBLE
+	0x0006: true, // PT_CURRENCY
+	0x0007: true, // PT_APPTIME
+	0x000a: true, // PT_ERROR
+	0x000b: true, // PT_BOOLEAN
+	0x0014: true, // PT_I8
+	0x0040: true, // PT_SYSTIME
+}
+
+// dumpTableContext prints the table context columns and rows of the node.
+//
+// References "[MS-PST] 2.3.4 Table Context (TC)":
+// Values of fixed size types up to 8 bytes are stored in the row, other values are referenced by a HNID.
+func (dumper *dumper) dumpTableContext(identifier int) error {
+	data, err := dumper.getNodeData(identifier)
+
+	if err != nil {
+		return err
+	}
+
+	tableContextInfo, err := pff.GetTableContextInfo(data)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Table context of node 0x%x:\n", identifier)
+	fmt.Printf("\tColumns:\t\t%d\n", tableContextInfo.ColumnCount)
+	fmt.Printf("\tRow size:\t\t%d (8 and 4 bytes columns end %d, 2 bytes columns end %d, 1 byte columns end %d)\n", tableContextInfo.RowSize, tableContextInfo.FourByteColumnsEnd, tableContextInfo.TwoByteColumnsEnd, tableContextInfo.OneByteColumnsEnd)
+	fmt.Printf("\tRow index:\t\t0x%x\n", tableContextInfo.RowIndexIdentifier)
+	fmt.Printf("\tRow matrix:\t\t0x%x\n\n", tableContextInfo.RowMatrixIdentifier)
+
+	for i, column := range tableContextInfo.Columns {
+		fmt.Printf("\tColumn %d: property 0x%04x, type 0x%04x, offset %d, size %d, cell existence bit %d\n", i, column.PropertyID, column.PropertyType, column.Offset, column.Size, column.CellExistenceBit)
+	}
+
+	if tableContextInfo.RowMatrixIdentifier&0x1f != 0 {
+		return fmt.Errorf("the row matrix is stored in local descriptor node 0x%x, which is not supported", tableContextInfo.RowMatrixIdentifier)
+	}
+
+	rows, err := pff.GetTableContextRows(data, tableContextInfo)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nRows:\t\t\t\t%d\n", len(rows))
+
+	for i, row := range rows {
+		fmt.Printf("\nRow %d:\n", i)
+
+		for _, column := range tableContextInfo.Columns {
+			value, ok := tableContextInfo.GetCell(row, column)
+
+			if !ok {
+				continue
+			}
+
+			fmt.Printf("\tProperty 0x%04x, type 0x%04x: ", column.PropertyID, column.PropertyType)
+
+			if tableContextFixedPropertyTypes[column.PropertyType] || len(value) != 4 {
+				fmt.Printf("value %x\n", value)
+			} else {
+				printHeapValue(data, int(binary.LittleEndian.Uint32(value)))
+			}
+		}
+	}
+
+	return nil
+}
+
+// runDump runs the dump command.
+func runDump(arguments []string) error {
+	if len(arguments) < 2 {
+		fmt.Println(dumpUsage)
+
+		return errors.New("missing structure or file argument")
+	}
+
+	structure := arguments[0]
+
+	dumper, err := newDumper(arguments[1])
+
+	if err != nil {
+		return err
+	}
+
+	identifier := -1
+
+	if len(arguments) > 2 {
+		parsedIdentifier, err := strconv.ParseInt(arguments[2], 0, 64)
+
+		if err != nil {
+			return fmt.Errorf("invalid identifier: %s", arguments[2])
+		}
+
+		identifier = int(parsedIdentifier)
+	} else if structure != "nbt" && structure != "bbt" {
+		return errors.New("missing identifier argument")
+	}
+
+	switch structure {
+	case "nbt", "bbt":
+		var btreeNode pff.BTreeNode
+
+		if identifier != -1 {
+			btreeNode = pff.NewBTreeNode(identifier)
+		} else if structure == "nbt" {
+			btreeNode, err = dumper.PFF.GetNodeBTree(dumper.FormatType)
+		} else {
+			btreeNode, err = dumper.PFF.GetBlockBTree(dumper.FormatType)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return dumper.dumpBTreePage(btreeNode)
+	case "node":
+		return dumper.dumpNode(identifier)
+	case "block":
+		return dumper.dumpBlock(identifier)
+	case "local-descriptors":
+		return dumper.dumpNodeLocalDescriptors(identifier)
+	case "heap":
+		return dumper.dumpHeap(identifier)
+	case "pc":
+		return dumper.dumpPropertyContext(identifier)
+	case "tc":
+		return dumper.dumpTableContext(identifier)
+	default:
+		fmt.Println(dumpUsage)
+
+		return fmt.Errorf("unsupported structure: %s", structure)
+	}
+}
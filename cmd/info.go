@@ -0,0 +1,315 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	pff "pff/pkg"
+)
+
+// BTreeInfo represents the information about a b-tree.
+type BTreeInfo struct {
+	RootOffset     int `json:"rootOffset"`
+	Depth          int `json:"depth"`
+	LeafEntryCount int `json:"leafEntryCount"`
+}
+
+// Info represents the information about a Personal Folder File.
+type Info struct {
+	File                string         `json:"file"`
+	FileSize            int64          `json:"fileSize"`
+	HeaderFileSize      int            `json:"headerFileSize"`
+	ContentType         string         `json:"contentType"`
+	FormatType          string         `json:"formatType"`
+	FormatVersion       int            `json:"formatVersion"`
+	ClientVersion       int            `json:"clientVersion"`
+	EncryptionType      string         `json:"encryptionType"`
+	NodeBTree           BTreeInfo      `json:"nodeBTree"`
+	BlockBTree          BTreeInfo      `json:"blockBTree"`
+	AllocationMapStatus string         `json:"allocationMapStatus"`
+	StoreName           string         `json:"storeName"`
+	StoreError          string         `json:"storeError,omitempty"`
+	IsPasswordProtected bool           `json:"passwordProtected"`
+	FolderCount         int            `json:"folderCount"`
+	ItemCount           int            `json:"itemCount"`
+	MessageClassCounts  map[string]int `json:"messageClassCounts"`
+}
+
+// unknownMessageClass is used for items of which the message class could not be read.
+const unknownMessageClass = "(unknown)"
+
+// getBTreeInfo returns the information about the b-tree starting at the root node and its leaf node entries.
+func getBTreeInfo(pst *pff.PFF, formatType string, rootNode pff.BTreeNode) (BTreeInfo, []pff.BTreeNodeEntry, error) {
+	rootNodeLevel, err := pst.GetBTreeNodeLevel(formatType, rootNode)
+
+	if err != nil {
+		return BTreeInfo{}, nil, err
+	}
+
+	leafNodeEntries, err := pst.GetBTreeLeafNodeEntries(formatType, rootNode)
+
+	if err != nil {
+		return BTreeInfo{}, nil, err
+	}
+
+	return BTreeInfo{
+		RootOffset:     rootNode.StartOffset,
+		Depth:          rootNodeLevel + 1,
+		LeafEntryCount: len(leafNodeEntries),
+	}, leafNodeEntries, nil
+}
+
+// getMessageClassCounts returns the amount of folders and the amount of items per message class.
+// Items of which the property context can not be read (yet) are counted as unknown.
+// The data blocks are looked up in the block b-tree entries instead of searching the block b-tree per item.
+func getMessageClassCounts(pst *pff.PFF, formatType string, encryptionType string, codePage int, nodeBTreeEntries []pff.BTreeNodeEntry, blockBTreeEntries []pff.BTreeNodeEntry) (int, map[string]int) {
+	folderCount := 0
+	messageClassCounts := make(map[string]int)
+	blockBTreeEntryMap := pff.GetBTreeNodeEntryMap(blockBTreeEntries)
+
+	for _, nodeBTreeEntry := range nodeBTreeEntries {
+		switch pff.GetNodeIdentifierType(nodeBTreeEntry.Identifier) {
+		case pff.NodeIdentifierTypeNormalFolder:
+			folderCount++
+		case pff.NodeIdentifierTypeNormalMessage:
+			messageClass := unknownMessageClass
+			propertyContext, err := pst.GetPropertyContextOfNodeEntry(formatType, encryptionType, nodeBTreeEntry, blockBTreeEntryMap)
+
+			if err == nil {
+				if value, err := propertyContext.GetString(pff.PropertyIDMessageClass, codePage); err == nil {
+					messageClass = value
+				}
+			}
+
+			messageClassCounts[messageClass]++
+		}
+	}
+
+	return folderCount, messageClassCounts
+}
+
+// getMessageStoreInfo returns the display name of the message store and if it is password protected.
+func getMessageStoreInfo(pst *pff.PFF, formatType string, codePage int) (string, bool, error) {
+	messageStore, err := pst.GetMessageStore(formatType)
+
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read message store: %s", err)
+	}
+
+	storeName, err := messageStore.GetDisplayName(codePage)
+
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read message store name: %s", err)
+	}
+
+	return storeName, messageStore.IsPasswordProtected(), nil
+}
+
+// getInfo returns the information about the Personal Folder File.
+// The code page is used for PT_STRING8 values (ANSI/32-bit PFF files), which do not store their code page.
+func getInfo(filePath string, codePage int) (Info, error) {
+	pst := pff.New(filePath)
+
+	fileInfo, err := os.Stat(filePath)
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	header, err := pst.GetHeader()
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	if !pst.IsValidSignature(header) {
+		return Info{}, errors.New("invalid Personal Folder File signature")
+	}
+
+	contentType, err := pst.GetContentType(header)
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	formatType, err := pst.GetFormatType(header)
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	encryptionType, err := pst.GetEncryptionType(formatType)
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	headerFileSize, err := pst.GetFileSize(formatType)
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	nodeBTree, err := pst.GetNodeBTree(formatType)
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	nodeBTreeInfo, nodeBTreeEntries, err := getBTreeInfo(&pst, formatType, nodeBTree)
+
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to walk node b-tree: %s", err)
+	}
+
+	blockBTree, err := pst.GetBlockBTree(formatType)
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	blockBTreeInfo, blockBTreeEntries, err := getBTreeInfo(&pst, formatType, blockBTree)
+
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to walk block b-tree: %s", err)
+	}
+
+	allocationMapStatus, err := pst.GetAllocationMapStatus(formatType)
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	storeName, isPasswordProtected, err := getMessageStoreInfo(&pst, formatType, codePage)
+	storeError := ""
+
+	if err != nil {
+		// The rest of the information is still useful to inspect a broken file.
+		storeError = err.Error()
+	}
+
+	folderCount, messageClassCounts := getMessageClassCounts(&pst, formatType, encryptionType, codePage, nodeBTreeEntries, blockBTreeEntries)
+	itemCount := 0
+
+	for _, messageClassCount := range messageClassCounts {
+		itemCount += messageClassCount
+	}
+
+	return Info{
+		File:                filePath,
+		FileSize:            fileInfo.Size(),
+		HeaderFileSize:      headerFileSize,
+		ContentType:         contentType,
+		FormatType:          formatType,
+		FormatVersion:       int(binary.LittleEndian.Uint16(header[10:12])),
+		ClientVersion:       int(binary.LittleEndian.Uint16(header[12:14])),
+		EncryptionType:      encryptionType,
+		NodeBTree:           nodeBTreeInfo,
+		BlockBTree:          blockBTreeInfo,
+		AllocationMapStatus: allocationMapStatus,
+		StoreName:           storeName,
+		StoreError:          storeError,
+		IsPasswordProtected: isPasswordProtected,
+		FolderCount:         folderCount,
+		ItemCount:           itemCount,
+		MessageClassCounts:  messageClassCounts,
+	}, nil
+}
+
+// printInfo prints the information in a human readable format.
+func printInfo(info Info) {
+	fmt.Printf("Personal Folder File information:\n")
+	fmt.Printf("\tFile:\t\t\t\t%s\n", info.File)
+	fmt.Printf("\tFile size:\t\t\t%d bytes\n", info.FileSize)
+	fmt.Printf("\tFile size (header):\t\t%d bytes\n", info.HeaderFileSize)
+	fmt.Printf("\tContent type:\t\t\t%s\n", info.ContentType)
+	fmt.Printf("\tFormat type:\t\t\t%s (version %d)\n", info.FormatType, info.FormatVersion)
+	fmt.Printf("\tClient version:\t\t\t%d\n", info.ClientVersion)
+	fmt.Printf("\tEncryption type:\t\t%s\n", info.EncryptionType)
+	fmt.Printf("\tAllocation map status:\t\t%s\n", info.AllocationMapStatus)
+	fmt.Printf("\n")
+	fmt.Printf("Message store:\n")
+
+	if info.StoreError != "" {
+		fmt.Printf("\tError:\t\t\t\t%s\n", info.StoreError)
+	} else {
+		fmt.Printf("\tDisplay name:\t\t\t%s\n", info.StoreName)
+		fmt.Printf("\tPassword protected:\t\t%t\n", info.IsPasswordProtected)
+	}
+
+	fmt.Printf("\tFolders:\t\t\t%d\n", info.FolderCount)
+	fmt.Printf("\tItems:\t\t\t\t%d\n", info.ItemCount)
+
+	messageClasses := make([]string, 0, len(info.MessageClassCounts))
+
+	for messageClass := range info.MessageClassCounts {
+		messageClasses = append(messageClasses, messageClass)
+	}
+
+	sort.Strings(messageClasses)
+
+	for _, messageClass := range messageClasses {
+		fmt.Printf("\t\t%s:\t\t%d\n", messageClass, info.MessageClassCounts[messageClass])
+	}
+
+	fmt.Printf("\n")
+	fmt.Printf("Node b-tree:\n")
+	fmt.Printf("\tRoot offset:\t\t\t%d\n", info.NodeBTree.RootOffset)
+	fmt.Printf("\tDepth:\t\t\t\t%d\n", info.NodeBTree.Depth)
+	fmt.Printf("\tNodes:\t\t\t\t%d\n", info.NodeBTree.LeafEntryCount)
+	fmt.Printf("\n")
+	fmt.Printf("Block b-tree:\n")
+	fmt.Printf("\tRoot offset:\t\t\t%d\n", info.BlockBTree.RootOffset)
+	fmt.Printf("\tDepth:\t\t\t\t%d\n", info.BlockBTree.Depth)
+	fmt.Printf("\tBlocks:\t\t\t\t%d\n", info.BlockBTree.LeafEntryCount)
+
+	if info.FileSize != int64(info.HeaderFileSize) {
+		fmt.Printf("\nWarning: the file size does not match the file size in the header, the file may be truncated.\n")
+	}
+}
+
+// runInfo runs the info command.
+func runInfo(arguments []string) error {
+	flagSet := flag.NewFlagSet("info", flag.ExitOnError)
+	isJSON := flagSet.Bool("json", false, "print the information as JSON")
+	codePage := flagSet.Int("code-page", 1252, "code page of ANSI (32-bit) strings")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return err
+	}
+
+	if flagSet.NArg() < 1 {
+		return errors.New("missing file argument")
+	}
+
+	filePath := flagSet.Arg(0)
+
+	// Allow flags after the file argument.
+	if err := flagSet.Parse(flagSet.Args()[1:]); err != nil {
+		return err
+	}
+
+	info, err := getInfo(filePath, *codePage)
+
+	if err != nil {
+		return err
+	}
+
+	if *isJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(info)
+	}
+
+	printInfo(info)
+
+	return nil
+}
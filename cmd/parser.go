@@ -3,15 +3,41 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	log "github.com/sirupsen/logrus"
-	pff "pff/pkg"
 )
 
+// usage prints the available commands.
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: pff <command> [arguments]\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  info [--json] [--code-page N] <file>  print information about the Personal Folder File\n")
+	fmt.Fprintf(os.Stderr, "  dump <structure> <file>               dump low-level structures (b-tree pages, blocks, heaps)\n")
+}
+
 func main() {
-	log.SetLevel(log.DebugLevel)
-	log.Infof("Starting go-pff...")
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
 
-	parser := pff.NewParser()
+	switch os.Args[1] {
+	case "info":
+		err = runInfo(os.Args[2:])
+	case "dump":
+		err = runDump(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+	default:
+		usage()
+		os.Exit(2)
+	}
 
-	parser.Parse("data/enron.pst")
+	if err != nil {
+		log.Fatalf("Failed to run %s: %s", os.Args[1], err)
+	}
 }
@@ -4,4 +4,5 @@ go 1.15
 
 require (
 	github.com/sirupsen/logrus v1.7.0
+	golang.org/x/text v0.3.8
 )
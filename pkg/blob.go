@@ -0,0 +1,120 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+	"unicode/utf16"
+)
+
+// blobReader reads little-endian values from binary property values (blobs).
+// The first out of bounds read is kept as the error, subsequent reads return zero values.
+type blobReader struct {
+	Data   []byte
+	Offset int
+	Err    error
+}
+
+// newBlobReader is a constructor for blob readers.
+func newBlobReader(data []byte) *blobReader {
+	return &blobReader{
+		Data: data,
+	}
+}
+
+// read returns the next size bytes.
+func (reader *blobReader) read(size int) []byte {
+	if reader.Err != nil {
+		return nil
+	}
+
+	if size < 0 || reader.Offset+size > len(reader.Data) {
+		reader.Err = errors.New("unexpected end of blob")
+
+		return nil
+	}
+
+	data := reader.Data[reader.Offset : reader.Offset+size]
+	reader.Offset += size
+
+	return data
+}
+
+// readUint8 returns the next byte.
+func (reader *blobReader) readUint8() int {
+	data := reader.read(1)
+
+	if data == nil {
+		return 0
+	}
+
+	return int(data[0])
+}
+
+// readUint16 returns the next 16-bit unsigned integer.
+func (reader *blobReader) readUint16() int {
+	data := reader.read(2)
+
+	if data == nil {
+		return 0
+	}
+
+	return int(binary.LittleEndian.Uint16(data))
+}
+
+// readUint32 returns the next 32-bit unsigned integer.
+func (reader *blobReader) readUint32() int {
+	data := reader.read(4)
+
+	if data == nil {
+		return 0
+	}
+
+	return int(binary.LittleEndian.Uint32(data))
+}
+
+// readInt32 returns the next 32-bit signed integer.
+func (reader *blobReader) readInt32() int {
+	data := reader.read(4)
+
+	if data == nil {
+		return 0
+	}
+
+	return int(int32(binary.LittleEndian.Uint32(data)))
+}
+
+// readFileTime returns the next FILETIME (100-nanosecond intervals since January 1, 1601 UTC).
+func (reader *blobReader) readFileTime() time.Time {
+	data := reader.read(8)
+
+	if data == nil {
+		return time.Time{}
+	}
+
+	return GetFileTime(binary.LittleEndian.Uint64(data))
+}
+
+// readUTF16 returns the next string of the given amount of UTF-16 (little-endian) characters.
+func (reader *blobReader) readUTF16(characterCount int) string {
+	data := reader.read(characterCount * 2)
+
+	if data == nil {
+		return ""
+	}
+
+	characters := make([]uint16, characterCount)
+
+	for i := 0; i < characterCount; i++ {
+		characters[i] = binary.LittleEndian.Uint16(data[i*2 : i*2+2])
+	}
+
+	return string(utf16.Decode(characters))
+}
+
+// GetFileTime returns the time of the FILETIME value (100-nanosecond intervals since January 1, 1601 UTC).
+func GetFileTime(fileTime uint64) time.Time {
+	return time.Unix(int64(fileTime/10000000)-11644473600, int64(fileTime%10000000)*100).UTC()
+}
@@ -0,0 +1,224 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// BlockTrailer represents the trailer following the data of a block.
+type BlockTrailer struct {
+	Size       int
+	Signature  int
+	CRC        uint32
+	Identifier int
+}
+
+// IsInternalBlock returns true if the block identifier identifies an internal block.
+// Internal blocks contain data trees or local descriptors and are never encrypted.
+//
+// References "[MS-PST] 2.2.2.2 BID (Block ID)".
+func IsInternalBlock(identifier int) bool {
+	return identifier&0x02 != 0
+}
+
+// GetBlockTrailerSize returns the size of the block trailer.
+// The 64-bit 4k page trailer starts with the same fields as the 64-bit trailer, followed by
+// 8 bytes containing the uncompressed data size (these files support compressed blocks).
+func GetBlockTrailerSize(formatType string) (int, error) {
+	if formatType == FormatType64With4k {
+		return 24, nil
+	} else if formatType == FormatType64 {
+		return 16, nil
+	} else if formatType == FormatType32 {
+		return 12, nil
+	} else {
+		return -1, errors.New("unsupported format type")
+	}
+}
+
+// GetBlockAlignment returns the alignment of blocks (including the trailer).
+//
+// References "[MS-PST] 2.2.2.8 Blocks":
+// Blocks are aligned to 64 bytes, blocks of 64-bit 4k page files are aligned to 512 bytes.
+func GetBlockAlignment(formatType string) (int, error) {
+	if formatType == FormatType64With4k {
+		return 512, nil
+	} else if formatType == FormatType64 || formatType == FormatType32 {
+		return 64, nil
+	} else {
+		return -1, errors.New("unsupported format type")
+	}
+}
+
+// GetBlockTrailerOffset returns the file offset of the block trailer, which is at the end of the aligned block.
+func (pff *PFF) GetBlockTrailerOffset(formatType string, blockBTreeEntry BTreeNodeEntry) (int, error) {
+	blockOffset, err := blockBTreeEntry.GetFileOffset(formatType)
+
+	if err != nil {
+		return -1, err
+	}
+
+	blockSize, err := blockBTreeEntry.GetSize(formatType)
+
+	if err != nil {
+		return -1, err
+	}
+
+	blockTrailerSize, err := GetBlockTrailerSize(formatType)
+
+	if err != nil {
+		return -1, err
+	}
+
+	blockAlignment, err := GetBlockAlignment(formatType)
+
+	if err != nil {
+		return -1, err
+	}
+
+	alignedBlockSize := (blockSize + blockTrailerSize + blockAlignment - 1) / blockAlignment * blockAlignment
+
+	return blockOffset + alignedBlockSize - blockTrailerSize, nil
+}
+
+// GetBlockTrailer returns the block trailer of the block b-tree entry.
+//
+// References "[MS-PST] 2.2.2.8.1 BLOCKTRAILER".
+func (pff *PFF) GetBlockTrailer(formatType string, blockBTreeEntry BTreeNodeEntry) (BlockTrailer, error) {
+	blockTrailerOffset, err := pff.GetBlockTrailerOffset(formatType, blockBTreeEntry)
+
+	if err != nil {
+		return BlockTrailer{}, err
+	}
+
+	blockTrailerSize, err := GetBlockTrailerSize(formatType)
+
+	if err != nil {
+		return BlockTrailer{}, err
+	}
+
+	blockTrailer, err := pff.Read(blockTrailerSize, blockTrailerOffset)
+
+	if err != nil {
+		return BlockTrailer{}, err
+	}
+
+	if formatType == FormatType64 || formatType == FormatType64With4k {
+		return BlockTrailer{
+			Size:       int(binary.LittleEndian.Uint16(blockTrailer[0:2])),
+			Signature:  int(binary.LittleEndian.Uint16(blockTrailer[2:4])),
+			CRC:        binary.LittleEndian.Uint32(blockTrailer[4:8]),
+			Identifier: int(binary.LittleEndian.Uint64(blockTrailer[8:16])),
+		}, nil
+	}
+
+	// The 32-bit block trailer stores the identifier before the CRC.
+	return BlockTrailer{
+		Size:       int(binary.LittleEndian.Uint16(blockTrailer[0:2])),
+		Signature:  int(binary.LittleEndian.Uint16(blockTrailer[2:4])),
+		Identifier: int(binary.LittleEndian.Uint32(blockTrailer[4:8])),
+		CRC:        binary.LittleEndian.Uint32(blockTrailer[8:12]),
+	}, nil
+}
+
+// GetBlockSignature returns the expected signature of a block, calculated from the file offset and identifier.
+//
+// References "[MS-PST] Block Signature".
+func GetBlockSignature(fileOffset int, identifier int) int {
+	signature := fileOffset ^ identifier
+
+	return ((signature >> 16) ^ signature) & 0xffff
+}
+
+// GetBlockCRC returns the CRC of the (encrypted) block data.
+//
+// References "[MS-PST] CRC Calculation":
+// The CRC-32 is the same as the one used by compressed RTF.
+func GetBlockCRC(data []byte) uint32 {
+	return GetCompressedRTFCRC(data)
+}
+
+// GetBlockData returns the data of the block b-tree entry, decrypted if the block is encrypted.
+//
+// References "2.7. Encryption types":
+// Internal blocks are never encrypted.
+// Compressed blocks of 64-bit 4k page files are not decompressed.
+func (pff *PFF) GetBlockData(formatType string, encryptionType string, blockBTreeEntry BTreeNodeEntry) ([]byte, error) {
+	blockOffset, err := blockBTreeEntry.GetFileOffset(formatType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	blockSize, err := blockBTreeEntry.GetSize(formatType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	blockData, err := pff.Read(blockSize, blockOffset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if IsInternalBlock(blockBTreeEntry.Identifier) || encryptionType == EncryptionTypeNone {
+		return blockData, nil
+	} else if encryptionType == EncryptionTypePermute {
+		table := NewTable(blockOffset)
+
+		return table.Decrypt(blockData), nil
+	} else {
+		return nil, errors.New("unsupported encryption type")
+	}
+}
+
+// getNodeBlockIdentifier returns the identifier of the data block of the node b-tree entry.
+// The lowest bit of block identifiers is reserved and ignored when searching the block b-tree.
+func getNodeBlockIdentifier(formatType string, nodeBTreeEntry BTreeNodeEntry) (int, error) {
+	dataIdentifier, err := nodeBTreeEntry.GetDataIdentifier(formatType)
+
+	if err != nil {
+		return -1, err
+	}
+
+	if dataIdentifier == 0 {
+		return -1, errors.New("node has no data")
+	} else if IsInternalBlock(dataIdentifier) {
+		return -1, errors.New("node data stored in a data tree is not supported")
+	}
+
+	return dataIdentifier &^ 1, nil
+}
+
+// GetNodeData returns the (decrypted) data of the node.
+// Node data stored in a data tree (multiple blocks) is not supported.
+func (pff *PFF) GetNodeData(formatType string, nodeIdentifier int) ([]byte, error) {
+	encryptionType, err := pff.GetEncryptionType(formatType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodeBTreeEntry, err := pff.FindNodeBTreeEntry(formatType, nodeIdentifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	blockIdentifier, err := getNodeBlockIdentifier(formatType, nodeBTreeEntry)
+
+	if err != nil {
+		return nil, err
+	}
+
+	blockBTreeEntry, err := pff.FindBlockBTreeEntry(formatType, blockIdentifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pff.GetBlockData(formatType, encryptionType, blockBTreeEntry)
+}
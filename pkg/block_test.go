@@ -0,0 +1,258 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestGetBlockTrailer(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	// Block 0x4 is stored at offset 22528.
+	blockBTreeEntry, err := pst.FindBlockBTreeEntry(FormatType32, 0x4)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name                  string
+		formatType            string
+		expectedTrailerOffset int
+		expectedTrailer       BlockTrailer
+		isErrorPresent        bool
+	}{
+		{
+			name:                  "32-bit",
+			formatType:            FormatType32,
+			expectedTrailerOffset: 22644,
+			expectedTrailer:       BlockTrailer{Size: 100, Signature: 0x5804, CRC: 0xa58f046c, Identifier: 0x4},
+		},
+		{name: "unsupported format type", formatType: "unsupported", isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			blockTrailerOffset, err := pst.GetBlockTrailerOffset(test.formatType, blockBTreeEntry)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got offset %d", blockTrailerOffset)
+				}
+
+				if _, err := pst.GetBlockTrailer(test.formatType, blockBTreeEntry); err == nil {
+					t.Fatalf("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if blockTrailerOffset != test.expectedTrailerOffset {
+				t.Fatalf("expected offset %d, got %d", test.expectedTrailerOffset, blockTrailerOffset)
+			}
+
+			blockTrailer, err := pst.GetBlockTrailer(test.formatType, blockBTreeEntry)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if blockTrailer != test.expectedTrailer {
+				t.Fatalf("expected %+v, got %+v", test.expectedTrailer, blockTrailer)
+			}
+
+			blockOffset, err := blockBTreeEntry.GetFileOffset(test.formatType)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if signature := GetBlockSignature(blockOffset, blockBTreeEntry.Identifier); signature != test.expectedTrailer.Signature {
+				t.Fatalf("expected signature 0x%x, got 0x%x", test.expectedTrailer.Signature, signature)
+			}
+
+			// The CRC is calculated over the encrypted data as stored in the file.
+			blockData, err := pst.Read(test.expectedTrailer.Size, blockOffset)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if crc := GetBlockCRC(blockData); crc != test.expectedTrailer.CRC {
+				t.Fatalf("expected CRC 0x%x, got 0x%x", test.expectedTrailer.CRC, crc)
+			}
+		})
+	}
+}
+
+// getTestHeapOnNode returns a copy of the heap-on-node of the message store with the 16-bit value at the offset replaced.
+func getTestHeapOnNode(t *testing.T, offset int, value uint16) []byte {
+	pst := New("../data/32-bit.pst")
+
+	data, err := pst.GetNodeData(FormatType32, NodeBTreeIdentifierMessageStore)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if offset >= 0 {
+		binary.LittleEndian.PutUint16(data[offset:offset+2], value)
+	}
+
+	return data
+}
+
+func TestGetHeapOnNodeAllocations(t *testing.T) {
+	// The page map of the message store is at offset 180: the allocation count (7),
+	// the free count and the 8 allocation offsets.
+	tests := []struct {
+		name           string
+		data           []byte
+		expectedCount  int
+		isErrorPresent bool
+	}{
+		{name: "message store", data: getTestHeapOnNode(t, -1, 0), expectedCount: 7},
+		{name: "page map offset beyond data", data: getTestHeapOnNode(t, 0, 200), isErrorPresent: true},
+		{name: "allocation count beyond data", data: getTestHeapOnNode(t, 180, 8), isErrorPresent: true},
+		{name: "allocation end before start", data: getTestHeapOnNode(t, 186, 0x8), isErrorPresent: true},
+		{name: "allocation end beyond data", data: getTestHeapOnNode(t, 198, 0x100), isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			heapOnNodeHeader, err := GetHeapOnNodeHeader(test.data)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			allocations, err := GetHeapOnNodeAllocations(test.data, heapOnNodeHeader.PageMapOffset)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %d allocations", len(allocations))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(allocations) != test.expectedCount {
+				t.Fatalf("expected %d allocations, got %d", test.expectedCount, len(allocations))
+			}
+
+			expectedAllocation := HeapOnNodeAllocation{Identifier: 0x20, Offset: 12, Size: 8}
+
+			if allocations[0] != expectedAllocation {
+				t.Fatalf("expected %+v, got %+v", expectedAllocation, allocations[0])
+			}
+		})
+	}
+}
+
+func TestGetBTreeOnHeapRecords(t *testing.T) {
+	data := getTestHeapOnNode(t, -1, 0)
+
+	heapOnNodeHeader, err := GetHeapOnNodeHeader(data)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	btreeOnHeapHeader, err := GetBTreeOnHeapHeader(data, heapOnNodeHeader.UserRootIdentifier)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name              string
+		btreeOnHeapHeader BTreeOnHeapHeader
+		expectedCount     int
+		isErrorPresent    bool
+	}{
+		{name: "message store", btreeOnHeapHeader: btreeOnHeapHeader, expectedCount: 7},
+		{name: "empty", btreeOnHeapHeader: BTreeOnHeapHeader{KeySize: 2, EntrySize: 6}},
+		{name: "invalid root heap identifier", btreeOnHeapHeader: BTreeOnHeapHeader{KeySize: 2, EntrySize: 6, RootIdentifier: 0x120}, isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := GetBTreeOnHeapRecords(data, test.btreeOnHeapHeader)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %d records", len(records))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(records) != test.expectedCount {
+				t.Fatalf("expected %d records, got %d", test.expectedCount, len(records))
+			}
+
+			for _, record := range records {
+				if len(record.Key) != test.btreeOnHeapHeader.KeySize || len(record.Data) != test.btreeOnHeapHeader.EntrySize {
+					t.Fatalf("unexpected record size: %d, %d", len(record.Key), len(record.Data))
+				}
+			}
+		})
+	}
+}
+
+func TestGetNodeData(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	tests := []struct {
+		name           string
+		nodeIdentifier int
+		expectedSize   int
+		isErrorPresent bool
+	}{
+		{name: "message store", nodeIdentifier: NodeBTreeIdentifierMessageStore, expectedSize: 200},
+		// Node 0x1e1 has no data block.
+		{name: "node without data", nodeIdentifier: 0x1e1, isErrorPresent: true},
+		{name: "missing node", nodeIdentifier: 0x1fffe4, isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := pst.GetNodeData(FormatType32, test.nodeIdentifier)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %d bytes", len(data))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(data) != test.expectedSize {
+				t.Fatalf("expected %d bytes, got %d", test.expectedSize, len(data))
+			}
+
+			// The decrypted data starts with the heap-on-node header.
+			if _, err := GetHeapOnNodeHeader(data); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
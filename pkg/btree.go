@@ -374,6 +374,11 @@ func (pff *PFF) GetBTreeNodeEntries(formatType string, btreeNode BTreeNode) ([]B
 		return []BTreeNodeEntry{}, err
 	}
 
+	// Corrupt pages may contain an entry count or size which does not fit in the page.
+	if nodeEntrySize < 8 || nodeEntryCount*nodeEntrySize > len(nodeEntries) {
+		return []BTreeNodeEntry{}, errors.New("invalid b-tree node entry count or size")
+	}
+
 	// Node entries
 	// (number of entries x entry size)
 	entries := make([]BTreeNodeEntry, nodeEntryCount)
@@ -398,6 +403,12 @@ func (pff *PFF) GetBTreeNodeEntries(formatType string, btreeNode BTreeNode) ([]B
 }
 
 // FindBTreeNode walks the b-tree and finds the node with the given identifier.
+// An empty b-tree node entry is returned if the identifier is not found.
+//
+// References "5. The index b-tree":
+// The branch node entries are sorted by their key, which is the first identifier of the child node,
+// so only the child of the last branch node entry with a key lower than or equal to the identifier is walked.
+// The level of a branch node's child nodes must be lower than the level of the branch node.
 func (pff *PFF) FindBTreeNode(formatType string, btreeNode BTreeNode, identifier int) (BTreeNodeEntry, error) {
 	btreeNodeEntries, err := pff.GetBTreeNodeEntries(formatType, btreeNode)
 
@@ -414,49 +425,167 @@ func (pff *PFF) FindBTreeNode(formatType string, btreeNode BTreeNode, identifier
 	if btreeNodeLevel > 0 {
 		// Branch node entries
 		// Branch node entries point to other branch nodes.
+		childBTreeNodeEntryIndex := -1
 
 		for i := 0; i < len(btreeNodeEntries); i++ {
-			btreeNodeEntry := btreeNodeEntries[i]
-
-			if btreeNodeEntry.Identifier == identifier {
-				return btreeNodeEntry, nil
+			if btreeNodeEntries[i].Identifier > identifier {
+				break
 			}
 
-			btreeNodeEntryOffset, err := pff.GetBTreeBranchNodeEntryOffset(formatType, btreeNodeEntry.Data)
+			childBTreeNodeEntryIndex = i
+		}
 
-			if err != nil {
-				return BTreeNodeEntry{}, err
-			}
+		if childBTreeNodeEntryIndex == -1 {
+			return BTreeNodeEntry{}, nil
+		}
+
+		btreeNodeEntryOffset, err := pff.GetBTreeBranchNodeEntryOffset(formatType, btreeNodeEntries[childBTreeNodeEntryIndex].Data)
 
-			btreeNodeEntryNode := NewBTreeNode(btreeNodeEntryOffset)
+		if err != nil {
+			return BTreeNodeEntry{}, err
+		}
 
-			// Recursively walk through the branch node entries.
-			btreeNodeEntry, err = pff.FindBTreeNode(formatType, btreeNodeEntryNode, identifier)
+		childBTreeNode := NewBTreeNode(btreeNodeEntryOffset)
 
-			if err != nil {
-				return BTreeNodeEntry{}, nil
-			}
+		childBTreeNodeLevel, err := pff.GetBTreeNodeLevel(formatType, childBTreeNode)
 
-			if btreeNodeEntry.Identifier == identifier {
-				return btreeNodeEntry, nil
-			}
+		if err != nil {
+			return BTreeNodeEntry{}, err
 		}
-	} else {
-		// Leaf node entries
-		// Leaf node entries point to data and the local descriptors.
 
-		for i := 0; i < len(btreeNodeEntries); i++ {
-			btreeNodeEntry := btreeNodeEntries[i]
+		if childBTreeNodeLevel >= btreeNodeLevel {
+			return BTreeNodeEntry{}, errors.New("invalid b-tree node level")
+		}
 
-			if btreeNodeEntry.Identifier == identifier {
-				return btreeNodeEntry, nil
-			}
+		// Recursively walk through the branch node entries.
+		return pff.FindBTreeNode(formatType, childBTreeNode, identifier)
+	}
+
+	// Leaf node entries
+	// Leaf node entries point to data and the local descriptors.
+	for i := 0; i < len(btreeNodeEntries); i++ {
+		btreeNodeEntry := btreeNodeEntries[i]
+
+		if btreeNodeEntry.Identifier == identifier {
+			return btreeNodeEntry, nil
 		}
 	}
 
 	return BTreeNodeEntry{}, nil
 }
 
+// FindNodeBTreeEntry returns the node b-tree entry of the node identifier.
+func (pff *PFF) FindNodeBTreeEntry(formatType string, nodeIdentifier int) (BTreeNodeEntry, error) {
+	nodeBTree, err := pff.GetNodeBTree(formatType)
+
+	if err != nil {
+		return BTreeNodeEntry{}, err
+	}
+
+	nodeBTreeEntry, err := pff.FindBTreeNode(formatType, nodeBTree, nodeIdentifier)
+
+	if err != nil {
+		return BTreeNodeEntry{}, err
+	}
+
+	// An empty entry is returned if the node is not found.
+	if len(nodeBTreeEntry.Data) == 0 || nodeBTreeEntry.Identifier != nodeIdentifier {
+		return BTreeNodeEntry{}, errors.New("node not found")
+	}
+
+	return nodeBTreeEntry, nil
+}
+
+// FindBlockBTreeEntry returns the block b-tree entry of the block identifier.
+// The lowest bit of block identifiers is reserved and ignored when searching the block b-tree.
+func (pff *PFF) FindBlockBTreeEntry(formatType string, blockIdentifier int) (BTreeNodeEntry, error) {
+	blockBTree, err := pff.GetBlockBTree(formatType)
+
+	if err != nil {
+		return BTreeNodeEntry{}, err
+	}
+
+	blockIdentifier &^= 1
+
+	blockBTreeEntry, err := pff.FindBTreeNode(formatType, blockBTree, blockIdentifier)
+
+	if err != nil {
+		return BTreeNodeEntry{}, err
+	}
+
+	// An empty entry is returned if the block is not found.
+	if len(blockBTreeEntry.Data) == 0 || blockBTreeEntry.Identifier != blockIdentifier {
+		return BTreeNodeEntry{}, errors.New("block not found")
+	}
+
+	return blockBTreeEntry, nil
+}
+
+// GetBTreeLeafNodeEntries walks the b-tree and returns all leaf node entries.
+//
+// References "5. The index b-tree":
+// The level of a branch node's child nodes must be lower than the level of the branch node.
+func (pff *PFF) GetBTreeLeafNodeEntries(formatType string, btreeNode BTreeNode) ([]BTreeNodeEntry, error) {
+	btreeNodeEntries, err := pff.GetBTreeNodeEntries(formatType, btreeNode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	btreeNodeLevel, err := pff.GetBTreeNodeLevel(formatType, btreeNode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if btreeNodeLevel == 0 {
+		return btreeNodeEntries, nil
+	}
+
+	var leafNodeEntries []BTreeNodeEntry
+
+	for _, btreeNodeEntry := range btreeNodeEntries {
+		btreeNodeEntryOffset, err := pff.GetBTreeBranchNodeEntryOffset(formatType, btreeNodeEntry.Data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		childBTreeNode := NewBTreeNode(btreeNodeEntryOffset)
+
+		childBTreeNodeLevel, err := pff.GetBTreeNodeLevel(formatType, childBTreeNode)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if childBTreeNodeLevel >= btreeNodeLevel {
+			return nil, errors.New("invalid b-tree node level")
+		}
+
+		childLeafNodeEntries, err := pff.GetBTreeLeafNodeEntries(formatType, childBTreeNode)
+
+		if err != nil {
+			return nil, err
+		}
+
+		leafNodeEntries = append(leafNodeEntries, childLeafNodeEntries...)
+	}
+
+	return leafNodeEntries, nil
+}
+
+// GetBTreeNodeEntryMap returns the b-tree node entries by their identifier.
+func GetBTreeNodeEntryMap(btreeNodeEntries []BTreeNodeEntry) map[int]BTreeNodeEntry {
+	btreeNodeEntryMap := make(map[int]BTreeNodeEntry, len(btreeNodeEntries))
+
+	for _, btreeNodeEntry := range btreeNodeEntries {
+		btreeNodeEntryMap[btreeNodeEntry.Identifier] = btreeNodeEntry
+	}
+
+	return btreeNodeEntryMap
+}
+
 func (pff *PFF) ProcessNameToIDMap(formatType string) error {
 	nodeBTree, err := pff.GetNodeBTree(formatType)
 
@@ -594,24 +723,30 @@ func (pff *PFF) GetLocalDescriptorsEntries(formatType string, localDescriptors L
 	}
 
 	var localDescriptorEntries []byte
+	var localDescriptorEntrySize int
 
 	if localDescriptorNodeLevel > 0 {
 		// Branch nodes
 
 		if formatType == FormatType64 || formatType == FormatType64With4k {
+			localDescriptorEntrySize = 16
 			localDescriptorEntries, err = pff.Read(localDescriptorEntryCount * 16, localDescriptors.StartOffset + 8)
 		} else if formatType == FormatType32 {
+			localDescriptorEntrySize = 8
 			localDescriptorEntries, err = pff.Read(localDescriptorEntryCount * 8, localDescriptors.StartOffset + 4)
 		} else {
 			return nil, errors.New("unsupported format type")
 		}
 	} else {
 		// Leaf nodes
+		// Leaf node entries contain the identifier, data identifier and local descriptors identifier.
 
 		if formatType == FormatType64 || formatType == FormatType64With4k {
-			localDescriptorEntries, err = pff.Read(localDescriptorEntryCount * 16, localDescriptors.StartOffset + 8)
+			localDescriptorEntrySize = 24
+			localDescriptorEntries, err = pff.Read(localDescriptorEntryCount * 24, localDescriptors.StartOffset + 8)
 		} else if formatType == FormatType32 {
-			localDescriptorEntries, err = pff.Read(localDescriptorEntryCount * 8, localDescriptors.StartOffset + 4)
+			localDescriptorEntrySize = 12
+			localDescriptorEntries, err = pff.Read(localDescriptorEntryCount * 12, localDescriptors.StartOffset + 4)
 		} else {
 			return nil, errors.New("unsupported format type")
 		}
@@ -622,8 +757,15 @@ func (pff *PFF) GetLocalDescriptorsEntries(formatType string, localDescriptors L
 	}
 
 	for i := 0; i < localDescriptorEntryCount; i++ {
-		log.Debugf("Identifier: %d", binary.LittleEndian.Uint64(localDescriptorEntries[:8]))
-		log.Debugf("Offset: %d", binary.LittleEndian.Uint64(localDescriptorEntries[8:16]))
+		localDescriptorEntry := localDescriptorEntries[i * localDescriptorEntrySize : (i + 1) * localDescriptorEntrySize]
+
+		if formatType == FormatType32 {
+			log.Debugf("Identifier: %d", binary.LittleEndian.Uint32(localDescriptorEntry[:4]))
+			log.Debugf("Offset: %d", binary.LittleEndian.Uint32(localDescriptorEntry[4:8]))
+		} else {
+			log.Debugf("Identifier: %d", binary.LittleEndian.Uint64(localDescriptorEntry[:8]))
+			log.Debugf("Offset: %d", binary.LittleEndian.Uint64(localDescriptorEntry[8:16]))
+		}
 	}
 
 	return localDescriptorEntries, nil
@@ -675,3 +817,21 @@ func (pff *PFF) GetLocalDescriptors(formatType string, btreeNodeEntry BTreeNodeE
 
 	return nil
 }
+
+// Constants for identifying the node type, stored in the lowest 5 bits of node identifiers.
+//
+// References "[MS-PST] 2.2.2.1 NID (Node ID)".
+const (
+	NodeIdentifierTypeHID               = 0x00
+	NodeIdentifierTypeInternal          = 0x01
+	NodeIdentifierTypeNormalFolder      = 0x02
+	NodeIdentifierTypeSearchFolder      = 0x03
+	NodeIdentifierTypeNormalMessage     = 0x04
+	NodeIdentifierTypeAttachment        = 0x05
+	NodeIdentifierTypeAssociatedMessage = 0x08
+)
+
+// GetNodeIdentifierType returns the node type of the node identifier.
+func GetNodeIdentifierType(identifier int) int {
+	return identifier & 0x1f
+}
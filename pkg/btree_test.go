@@ -0,0 +1,153 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// getTestCorruptFile returns a copy of data/32-bit.pst with the 32-bit value at the offset replaced.
+func getTestCorruptFile(t *testing.T, offset int, value uint32) PFF {
+	data, err := ioutil.ReadFile("../data/32-bit.pst")
+
+	if err != nil {
+		t.Fatalf("failed to read test file: %s", err)
+	}
+
+	binary.LittleEndian.PutUint32(data[offset:offset+4], value)
+
+	filePath := filepath.Join(t.TempDir(), "corrupt.pst")
+
+	if err := ioutil.WriteFile(filePath, data, 0600); err != nil {
+		t.Fatalf("failed to write test file: %s", err)
+	}
+
+	return New(filePath)
+}
+
+func TestFindBTreeNode(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	nodeBTree, err := pst.GetNodeBTree(FormatType32)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	blockBTree, err := pst.GetBlockBTree(FormatType32)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The child offset of the first root branch node entry of the node b-tree points past the end of the file.
+	corruptPST := getTestCorruptFile(t, nodeBTree.StartOffset+8, 0x7fffffff)
+
+	// The child offset of the first root branch node entry of the node b-tree points back to the root node.
+	cyclicPST := getTestCorruptFile(t, nodeBTree.StartOffset+8, uint32(nodeBTree.StartOffset))
+
+	tests := []struct {
+		name           string
+		pst            PFF
+		btreeNode      BTreeNode
+		identifier     int
+		expected       int
+		isErrorPresent bool
+	}{
+		{name: "message store", pst: pst, btreeNode: nodeBTree, identifier: 0x21, expected: 0x21},
+		{name: "message", pst: pst, btreeNode: nodeBTree, identifier: 0x200024, expected: 0x200024},
+		{name: "block", pst: pst, btreeNode: blockBTree, identifier: 0x5c, expected: 0x5c},
+		{name: "lower than the first key", pst: pst, btreeNode: nodeBTree, identifier: 0x1, expected: 0},
+		{name: "missing node", pst: pst, btreeNode: nodeBTree, identifier: 0x1fffe4, expected: 0},
+		{name: "unreadable child node", pst: corruptPST, btreeNode: nodeBTree, identifier: 0x21, isErrorPresent: true},
+		{name: "child node level not lower", pst: cyclicPST, btreeNode: nodeBTree, identifier: 0x21, isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			btreeNodeEntry, err := test.pst.FindBTreeNode(FormatType32, test.btreeNode, test.identifier)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got 0x%x", btreeNodeEntry.Identifier)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if btreeNodeEntry.Identifier != test.expected {
+				t.Fatalf("expected 0x%x, got 0x%x", test.expected, btreeNodeEntry.Identifier)
+			}
+		})
+	}
+}
+
+func TestGetBTreeLeafNodeEntries(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	nodeBTree, err := pst.GetNodeBTree(FormatType32)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	blockBTree, err := pst.GetBlockBTree(FormatType32)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The child offset of the first root branch node entry of the node b-tree points back to the root node.
+	cyclicPST := getTestCorruptFile(t, nodeBTree.StartOffset+8, uint32(nodeBTree.StartOffset))
+
+	tests := []struct {
+		name           string
+		pst            PFF
+		btreeNode      BTreeNode
+		expected       int
+		isErrorPresent bool
+	}{
+		{name: "node b-tree", pst: pst, btreeNode: nodeBTree, expected: 34},
+		{name: "block b-tree", pst: pst, btreeNode: blockBTree, expected: 26},
+		{name: "child node level not lower", pst: cyclicPST, btreeNode: nodeBTree, isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			leafNodeEntries, err := test.pst.GetBTreeLeafNodeEntries(FormatType32, test.btreeNode)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %d entries", len(leafNodeEntries))
+				}
+
+				if err.Error() != "invalid b-tree node level" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(leafNodeEntries) != test.expected {
+				t.Fatalf("expected %d entries, got %d", test.expected, len(leafNodeEntries))
+			}
+
+			btreeNodeEntryMap := GetBTreeNodeEntryMap(leafNodeEntries)
+
+			if len(btreeNodeEntryMap) != test.expected {
+				t.Fatalf("expected %d mapped entries, got %d", test.expected, len(btreeNodeEntryMap))
+			}
+		})
+	}
+}
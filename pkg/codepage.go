@@ -0,0 +1,142 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"bytes"
+	"errors"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Constants for identifying the properties containing the code page of PT_STRING8 values.
+//
+// References "[MS-OXPROPS] PidTagMessageCodepage" and "[MS-OXPROPS] PidTagInternetCodepage".
+const (
+	PropertyIDMessageCodePage  = 0x3ffd
+	PropertyIDInternetCodePage = 0x3fde
+)
+
+// codePageEncodings maps Windows code page identifiers to their encoding.
+// UTF-16 (1200) is not included since PT_STRING8 values are never UTF-16, PT_UNICODE is used instead.
+var codePageEncodings = map[int]encoding.Encoding{
+	437:   charmap.CodePage437,
+	850:   charmap.CodePage850,
+	852:   charmap.CodePage852,
+	855:   charmap.CodePage855,
+	858:   charmap.CodePage858,
+	860:   charmap.CodePage860,
+	862:   charmap.CodePage862,
+	863:   charmap.CodePage863,
+	865:   charmap.CodePage865,
+	866:   charmap.CodePage866,
+	874:   charmap.Windows874,
+	932:   japanese.ShiftJIS,
+	936:   simplifiedchinese.GBK,
+	949:   korean.EUCKR,
+	950:   traditionalchinese.Big5,
+	1250:  charmap.Windows1250,
+	1251:  charmap.Windows1251,
+	1252:  charmap.Windows1252,
+	1253:  charmap.Windows1253,
+	1254:  charmap.Windows1254,
+	1255:  charmap.Windows1255,
+	1256:  charmap.Windows1256,
+	1257:  charmap.Windows1257,
+	1258:  charmap.Windows1258,
+	10000: charmap.Macintosh,
+	10007: charmap.MacintoshCyrillic,
+	20127: charmap.Windows1252,
+	20866: charmap.KOI8R,
+	20932: japanese.EUCJP,
+	20936: simplifiedchinese.GBK,
+	21866: charmap.KOI8U,
+	28591: charmap.ISO8859_1,
+	28592: charmap.ISO8859_2,
+	28593: charmap.ISO8859_3,
+	28594: charmap.ISO8859_4,
+	28595: charmap.ISO8859_5,
+	28596: charmap.ISO8859_6,
+	28597: charmap.ISO8859_7,
+	28598: charmap.ISO8859_8,
+	28599: charmap.ISO8859_9,
+	28603: charmap.ISO8859_13,
+	28605: charmap.ISO8859_15,
+	38598: charmap.ISO8859_8,
+	50220: japanese.ISO2022JP,
+	50221: japanese.ISO2022JP,
+	50222: japanese.ISO2022JP,
+	51932: japanese.EUCJP,
+	51936: simplifiedchinese.GBK,
+	51949: korean.EUCKR,
+	52936: simplifiedchinese.HZGB2312,
+	54936: simplifiedchinese.GB18030,
+	65001: unicode.UTF8,
+}
+
+// GetCodePageEncoding returns the encoding of the Windows code page identifier.
+func GetCodePageEncoding(codePage int) (encoding.Encoding, error) {
+	codePageEncoding, ok := codePageEncodings[codePage]
+
+	if !ok {
+		return nil, errors.New("unsupported code page")
+	}
+
+	return codePageEncoding, nil
+}
+
+// StringDecoder represents a decoder for PT_STRING8 values.
+type StringDecoder struct {
+	FallbackCodePage int
+}
+
+// NewStringDecoder is a constructor for creating string decoders.
+// The fallback code page is used when none of the code pages passed to DecodeString8 are supported.
+func NewStringDecoder(fallbackCodePage int) StringDecoder {
+	return StringDecoder{
+		FallbackCodePage: fallbackCodePage,
+	}
+}
+
+// GetCodePage returns the first supported code page in order of preference, otherwise the fallback code page.
+//
+// The code pages should be passed in order of preference, for example:
+// PR_MESSAGE_CODEPAGE, PR_INTERNET_CPID and the default code page of the message store.
+func (stringDecoder *StringDecoder) GetCodePage(codePages ...int) int {
+	for _, codePage := range codePages {
+		if _, ok := codePageEncodings[codePage]; ok {
+			return codePage
+		}
+	}
+
+	return stringDecoder.FallbackCodePage
+}
+
+// DecodeString8 returns the UTF-8 string of the PT_STRING8 value.
+//
+// References "[MS-PST] 2.3.3.3 PC BTH Record" and "[MS-OXCDATA] 2.11.1 Property Data Types":
+// PT_STRING8 values are stored in the code page of the message (ANSI/32-bit PFF files store all strings as PT_STRING8).
+func (stringDecoder *StringDecoder) DecodeString8(data []byte, codePages ...int) (string, error) {
+	// Strip the terminating NUL characters.
+	data = bytes.TrimRight(data, "\x00")
+
+	codePageEncoding, err := GetCodePageEncoding(stringDecoder.GetCodePage(codePages...))
+
+	if err != nil {
+		return "", err
+	}
+
+	decoded, err := codePageEncoding.NewDecoder().Bytes(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(decoded), nil
+}
@@ -0,0 +1,47 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"testing"
+)
+
+func TestDecodeString8(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		codePages []int
+		expected  string
+	}{
+		{name: "Windows-1252", data: []byte{0x63, 0x61, 0x66, 0xe9, 0x00}, codePages: []int{1252}, expected: "café"},
+		{name: "Windows-1250", data: []byte{0x8a, 0x9e}, codePages: []int{1250}, expected: "Šž"},
+		{name: "KOI8-R", data: []byte{0xf0, 0xd2, 0xc9}, codePages: []int{20866}, expected: "При"},
+		{name: "Shift-JIS", data: []byte{0x93, 0xfa, 0x96, 0x7b, 0x00, 0x00}, codePages: []int{932}, expected: "日本"},
+		{name: "first supported code page", data: []byte{0xe9}, codePages: []int{0, 1251}, expected: "й"},
+		{
+			// UTF-16 is not a PT_STRING8 code page, the next code page is used instead.
+			name:      "UTF-16 is skipped",
+			data:      []byte{0x41, 0xe9},
+			codePages: []int{1200, 1252},
+			expected:  "Aé",
+		},
+		{name: "fallback code page", data: []byte{0xe9}, codePages: []int{12345}, expected: "é"},
+		{name: "empty", data: nil, codePages: []int{1252}, expected: ""},
+	}
+
+	stringDecoder := NewStringDecoder(1252)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoded, err := stringDecoder.DecodeString8(test.data, test.codePages...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if decoded != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, decoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// Constants for identifying the compression type of compressed RTF.
+//
+// References "[MS-OXRTFCP] Compressed RTF header".
+const (
+	CompressedRTFTypeCompressed   = 0x75465a4c // "LZFu"
+	CompressedRTFTypeUncompressed = 0x414c454d // "MELA"
+)
+
+// compressedRTFPrebuffer is the initial content of the dictionary.
+//
+// References "[MS-OXRTFCP] Dictionary initialization".
+const compressedRTFPrebuffer = "{\\rtf1\\ansi\\mac\\deff0\\deftab720{\\fonttbl;}" +
+	"{\\f0\\fnil \\froman \\fswiss \\fmodern \\fscript \\fdecor MS Sans SerifSymbolArialTimes New RomanCourier" +
+	"{\\colortbl\\red0\\green0\\blue0\r\n\\par \\pard\\plain\\f0\\fs20\\b\\i\\u\\tab\\tx"
+
+// CompressedRTFHeader represents the header of a compressed RTF (PR_RTF_COMPRESSED) body.
+type CompressedRTFHeader struct {
+	CompressedSize   int
+	UncompressedSize int
+	CompressionType  int
+	CRC              uint32
+}
+
+// GetCompressedRTFHeader returns the header of the compressed RTF.
+//
+// References "[MS-OXRTFCP] Compressed RTF header":
+// The header consists of 16 bytes; the compressed size includes the header excluding the compressed size field itself.
+func GetCompressedRTFHeader(compressedRTF []byte) (CompressedRTFHeader, error) {
+	if len(compressedRTF) < 16 {
+		return CompressedRTFHeader{}, errors.New("compressed RTF is too small")
+	}
+
+	return CompressedRTFHeader{
+		CompressedSize:   int(binary.LittleEndian.Uint32(compressedRTF[0:4])),
+		UncompressedSize: int(binary.LittleEndian.Uint32(compressedRTF[4:8])),
+		CompressionType:  int(binary.LittleEndian.Uint32(compressedRTF[8:12])),
+		CRC:              binary.LittleEndian.Uint32(compressedRTF[12:16]),
+	}, nil
+}
+
+// GetCompressedRTFCRC returns the CRC of the compressed RTF data following the header.
+//
+// References "[MS-OXRTFCP] CRC calculation":
+// The CRC-32 uses the standard polynomial but starts with 0 and does not invert the result.
+func GetCompressedRTFCRC(data []byte) uint32 {
+	return ^crc32.Update(0xffffffff, crc32.IEEETable, data)
+}
+
+// DecompressRTF returns the RTF of a PR_RTF_COMPRESSED body.
+//
+// References "[MS-OXRTFCP] Decompression":
+// The compressed data consists of runs of a control byte followed by up to 8 tokens.
+// Each control bit (least significant first) identifies the token as a literal byte (0)
+// or a 2 byte big-endian dictionary reference (1) with a 12-bit offset and a 4-bit length.
+func DecompressRTF(compressedRTF []byte) ([]byte, error) {
+	header, err := GetCompressedRTFHeader(compressedRTF)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if header.CompressedSize+4 > len(compressedRTF) || header.CompressedSize < 12 {
+		return nil, errors.New("invalid compressed RTF size")
+	}
+
+	data := compressedRTF[16 : header.CompressedSize+4]
+
+	if header.CompressionType == CompressedRTFTypeUncompressed {
+		if header.UncompressedSize < 0 || header.UncompressedSize > len(data) {
+			return nil, errors.New("invalid uncompressed RTF size")
+		}
+
+		return data[:header.UncompressedSize], nil
+	} else if header.CompressionType != CompressedRTFTypeCompressed {
+		return nil, errors.New("unsupported compressed RTF type")
+	}
+
+	if GetCompressedRTFCRC(data) != header.CRC {
+		return nil, errors.New("invalid compressed RTF CRC")
+	}
+
+	dictionary := make([]byte, 4096)
+	copy(dictionary, compressedRTFPrebuffer)
+	writeOffset := len(compressedRTFPrebuffer)
+
+	// The uncompressed size is not trusted for the preallocation since a corrupt header could claim up to 4 GiB.
+	// A 2 byte dictionary reference expands to at most 17 bytes, so the output is less than 9 times the input.
+	outputCapacity := header.UncompressedSize
+
+	if outputCapacity < 0 || outputCapacity > len(data)*9 {
+		outputCapacity = len(data) * 9
+	}
+
+	output := make([]byte, 0, outputCapacity)
+
+	for i := 0; i < len(data); {
+		control := data[i]
+		i++
+
+		for bit := 0; bit < 8 && i < len(data); bit++ {
+			if control&(1<<bit) == 0 {
+				// Literal
+				dictionary[writeOffset] = data[i]
+				writeOffset = (writeOffset + 1) % 4096
+				output = append(output, data[i])
+				i++
+
+				continue
+			}
+
+			// Dictionary reference
+			if i+2 > len(data) {
+				return nil, errors.New("truncated compressed RTF dictionary reference")
+			}
+
+			reference := int(binary.BigEndian.Uint16(data[i : i+2]))
+			i += 2
+
+			readOffset := reference >> 4
+			length := (reference & 0xf) + 2
+
+			if readOffset == writeOffset {
+				// The end of the compressed data is marked by a reference to the write offset.
+				return output, nil
+			}
+
+			for j := 0; j < length; j++ {
+				character := dictionary[(readOffset+j)%4096]
+				dictionary[writeOffset] = character
+				writeOffset = (writeOffset + 1) % 4096
+				output = append(output, character)
+			}
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,122 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecompressRTF(t *testing.T) {
+	tests := []struct {
+		name           string
+		compressedRTF  []byte
+		expected       string
+		isErrorPresent bool
+	}{
+		{
+			// References "[MS-OXRTFCP] Example 1: Simple Compressed RTF".
+			name: "simple compressed RTF",
+			compressedRTF: []byte{
+				0x2d, 0x00, 0x00, 0x00, 0x2b, 0x00, 0x00, 0x00, 0x4c, 0x5a, 0x46, 0x75, 0xf1, 0xc5, 0xc7, 0xa7,
+				0x03, 0x00, 0x0a, 0x00, 0x72, 0x63, 0x70, 0x67, 0x31, 0x32, 0x35, 0x42, 0x32, 0x0a, 0xf3, 0x20,
+				0x68, 0x65, 0x6c, 0x09, 0x00, 0x20, 0x62, 0x77, 0x05, 0xb0, 0x6c, 0x64, 0x7d, 0x0a, 0x80, 0x0f,
+				0xa0,
+			},
+			expected: "{\\rtf1\\ansi\\ansicpg1252\\pard hello world}\r\n",
+		},
+		{
+			// References "[MS-OXRTFCP] Example 2: Reading a Token from the Dictionary that Crosses WritePosition".
+			name: "dictionary reference crossing the write offset",
+			compressedRTF: []byte{
+				0x1a, 0x00, 0x00, 0x00, 0x1c, 0x00, 0x00, 0x00, 0x4c, 0x5a, 0x46, 0x75, 0xe2, 0xd4, 0x4b, 0x51,
+				0x41, 0x00, 0x04, 0x20, 0x57, 0x58, 0x59, 0x5a, 0x0d, 0x6e, 0x7d, 0x01, 0x0e, 0xb0,
+			},
+			expected: "{\\rtf1 WXYZWXYZWXYZWXYZWXYZ}",
+		},
+		{
+			name: "uncompressed RTF",
+			compressedRTF: append([]byte{
+				0x11, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x4d, 0x45, 0x4c, 0x41, 0x00, 0x00, 0x00, 0x00,
+			}, "{\\rtf}"...),
+			expected: "{\\rtf",
+		},
+		{
+			name: "corrupt uncompressed size",
+			compressedRTF: []byte{
+				0x1a, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0x4c, 0x5a, 0x46, 0x75, 0xe2, 0xd4, 0x4b, 0x51,
+				0x41, 0x00, 0x04, 0x20, 0x57, 0x58, 0x59, 0x5a, 0x0d, 0x6e, 0x7d, 0x01, 0x0e, 0xb0,
+			},
+			expected: "{\\rtf1 WXYZWXYZWXYZWXYZWXYZ}",
+		},
+		{
+			name: "truncated header",
+			compressedRTF: []byte{
+				0x2d, 0x00, 0x00, 0x00, 0x2b, 0x00, 0x00, 0x00, 0x4c, 0x5a, 0x46, 0x75,
+			},
+			isErrorPresent: true,
+		},
+		{
+			name: "truncated data",
+			compressedRTF: []byte{
+				0x2d, 0x00, 0x00, 0x00, 0x2b, 0x00, 0x00, 0x00, 0x4c, 0x5a, 0x46, 0x75, 0xf1, 0xc5, 0xc7, 0xa7,
+				0x03, 0x00, 0x0a, 0x00, 0x72, 0x63, 0x70, 0x67,
+			},
+			isErrorPresent: true,
+		},
+		{
+			name: "invalid CRC",
+			compressedRTF: []byte{
+				0x1a, 0x00, 0x00, 0x00, 0x1c, 0x00, 0x00, 0x00, 0x4c, 0x5a, 0x46, 0x75, 0x00, 0x00, 0x00, 0x00,
+				0x41, 0x00, 0x04, 0x20, 0x57, 0x58, 0x59, 0x5a, 0x0d, 0x6e, 0x7d, 0x01, 0x0e, 0xb0,
+			},
+			isErrorPresent: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rtf, err := DecompressRTF(test.compressedRTF)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", rtf)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !bytes.Equal(rtf, []byte(test.expected)) {
+				t.Fatalf("expected %q, got %q", test.expected, rtf)
+			}
+		})
+	}
+}
+
+func TestGetCompressedRTFCRC(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected uint32
+	}{
+		{name: "empty", data: nil, expected: 0},
+		{
+			// References "[MS-OXRTFCP] Example 2": the CRC in the header covers the data following the header.
+			name:     "example 2",
+			data:     []byte{0x41, 0x00, 0x04, 0x20, 0x57, 0x58, 0x59, 0x5a, 0x0d, 0x6e, 0x7d, 0x01, 0x0e, 0xb0},
+			expected: 0x514bd4e2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if crc := GetCompressedRTFCRC(test.data); crc != test.expected {
+				t.Fatalf("expected 0x%08x, got 0x%08x", test.expected, crc)
+			}
+		})
+	}
+}
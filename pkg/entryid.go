@@ -0,0 +1,218 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"unicode/utf16"
+)
+
+// Constants for identifying entry ID types by their provider UID.
+//
+// References "[MS-OXCDATA] 2.2 EntryID and Related Types" and "[MS-PST] 2.4.3.2 Mapping between EntryID and NID".
+const (
+	EntryIDTypeStore              = "store"
+	EntryIDTypeOneOff             = "one-off"
+	EntryIDTypeAddressBook        = "address-book"
+	EntryIDTypeContactAddressBook = "contact-address-book"
+	EntryIDTypeWrapped            = "wrapped"
+)
+
+// Provider UIDs identifying the entry ID types which are not store entry IDs.
+var (
+	EntryIDProviderUIDOneOff             = []byte{0x81, 0x2b, 0x1f, 0xa4, 0xbe, 0xa3, 0x10, 0x19, 0x9d, 0x6e, 0x00, 0xdd, 0x01, 0x0f, 0x54, 0x02}
+	EntryIDProviderUIDAddressBook        = []byte{0xdc, 0xa7, 0x40, 0xc8, 0xc0, 0x42, 0x10, 0x1a, 0xb4, 0xb9, 0x08, 0x00, 0x2b, 0x2f, 0xe1, 0x82}
+	EntryIDProviderUIDContactAddressBook = []byte{0xfe, 0x42, 0xaa, 0x0a, 0x18, 0xc7, 0x1a, 0x10, 0xe8, 0x85, 0x0b, 0x65, 0x1c, 0x24, 0x00, 0x00}
+	EntryIDProviderUIDWrapped            = []byte{0xc0, 0x91, 0xad, 0xd3, 0x51, 0x9d, 0xcf, 0x11, 0xa4, 0xa9, 0x00, 0xaa, 0x00, 0x47, 0xfa, 0xa4}
+)
+
+// Constants for identifying the one-off entry ID flags.
+//
+// References "[MS-OXCDATA] 2.2.5.1 One-Off EntryID".
+const (
+	OneOffEntryIDFlagUnicode    = 0x8000
+	OneOffEntryIDFlagNoRichInfo = 0x0001
+)
+
+// EntryID represents an entry ID (PR_ENTRYID, PR_PARENT_ENTRYID, distribution list members and recipients).
+// Depending on the type only some fields are set.
+type EntryID struct {
+	Data        []byte
+	Flags       int
+	ProviderUID []byte
+	Type        string
+
+	// Store entry ID
+	NodeIdentifier int
+
+	// One-off entry ID
+	DisplayName  string
+	AddressType  string
+	EmailAddress string
+
+	// Address book entry ID
+	AddressBookType int
+	X500DN          string
+
+	// Wrapped (and contact address book) entry ID
+	WrappedType    int
+	WrappedEntryID *EntryID
+}
+
+// GetEntryID returns the entry ID which is classified by its provider UID.
+//
+// References "[MS-OXCDATA] 2.2 EntryID and Related Types", "[MS-OXOCNTC] WrappedEntryId" and "[MS-PST] 2.4.3.2 Mapping between EntryID and NID":
+// Entry IDs consist of 4 bytes flags and a 16 bytes provider UID followed by provider specific data.
+// The provider UID of store entry IDs is the record key of the message store, followed by the 4 bytes node identifier (NID).
+// Since the record key differs per message store, any other 24 bytes entry ID is assumed to be a store entry ID;
+// ResolveEntryID verifies the provider UID against the record key.
+// The code page (see StringDecoder.GetCodePage) is used for ANSI one-off entry IDs, falling back to Windows-1252 if unsupported.
+func GetEntryID(data []byte, codePage int) (EntryID, error) {
+	reader := newBlobReader(data)
+
+	entryID := EntryID{
+		Data:        data,
+		Flags:       reader.readUint32(),
+		ProviderUID: reader.read(16),
+	}
+
+	if reader.Err != nil {
+		return EntryID{}, errors.New("entry ID is too small")
+	}
+
+	if bytes.Equal(entryID.ProviderUID, EntryIDProviderUIDOneOff) {
+		entryID.Type = EntryIDTypeOneOff
+
+		_ = reader.readUint16() // Version
+		oneOffFlags := reader.readUint16()
+
+		if oneOffFlags&OneOffEntryIDFlagUnicode != 0 {
+			entryID.DisplayName = readEntryIDUTF16String(reader)
+			entryID.AddressType = readEntryIDUTF16String(reader)
+			entryID.EmailAddress = readEntryIDUTF16String(reader)
+		} else {
+			stringDecoder := NewStringDecoder(1252)
+
+			entryID.DisplayName, _ = stringDecoder.DecodeString8(readEntryIDString8(reader), codePage)
+			entryID.AddressType, _ = stringDecoder.DecodeString8(readEntryIDString8(reader), codePage)
+			entryID.EmailAddress, _ = stringDecoder.DecodeString8(readEntryIDString8(reader), codePage)
+		}
+	} else if bytes.Equal(entryID.ProviderUID, EntryIDProviderUIDAddressBook) {
+		entryID.Type = EntryIDTypeAddressBook
+
+		_ = reader.readUint32() // Version
+		entryID.AddressBookType = reader.readUint32()
+		entryID.X500DN = string(readEntryIDString8(reader))
+	} else if bytes.Equal(entryID.ProviderUID, EntryIDProviderUIDWrapped) {
+		entryID.Type = EntryIDTypeWrapped
+		entryID.WrappedType = reader.readUint8()
+
+		wrappedEntryID, err := GetEntryID(reader.read(len(data)-reader.Offset), codePage)
+
+		if err != nil {
+			return EntryID{}, err
+		}
+
+		entryID.WrappedEntryID = &wrappedEntryID
+	} else if bytes.Equal(entryID.ProviderUID, EntryIDProviderUIDContactAddressBook) {
+		entryID.Type = EntryIDTypeContactAddressBook
+
+		_ = reader.readUint32() // Version
+		entryID.WrappedType = reader.readUint32()
+		_ = reader.readUint32() // Index
+		wrappedEntryIDSize := reader.readUint32()
+
+		wrappedEntryID, err := GetEntryID(reader.read(wrappedEntryIDSize), codePage)
+
+		if err != nil {
+			return EntryID{}, err
+		}
+
+		entryID.WrappedEntryID = &wrappedEntryID
+	} else if len(data) == 24 {
+		// Unknown provider UID, assumed to be the record key of a message store.
+		entryID.Type = EntryIDTypeStore
+		entryID.NodeIdentifier = reader.readUint32()
+	} else {
+		return EntryID{}, errors.New("unsupported entry ID type")
+	}
+
+	if reader.Err != nil {
+		return EntryID{}, reader.Err
+	}
+
+	return entryID, nil
+}
+
+// readEntryIDString8 returns the next NUL-terminated 8-bit string (excluding the terminator).
+func readEntryIDString8(reader *blobReader) []byte {
+	if reader.Err != nil {
+		return nil
+	}
+
+	terminatorIndex := bytes.IndexByte(reader.Data[reader.Offset:], 0)
+
+	if terminatorIndex == -1 {
+		reader.Err = errors.New("unterminated entry ID string")
+
+		return nil
+	}
+
+	value := reader.read(terminatorIndex)
+	reader.read(1)
+
+	return value
+}
+
+// readEntryIDUTF16String returns the next NUL-terminated UTF-16 (little-endian) string.
+func readEntryIDUTF16String(reader *blobReader) string {
+	var characters []uint16
+
+	for reader.Err == nil {
+		data := reader.read(2)
+
+		if data == nil {
+			break
+		}
+
+		character := binary.LittleEndian.Uint16(data)
+
+		if character == 0 {
+			break
+		}
+
+		characters = append(characters, character)
+	}
+
+	return string(utf16.Decode(characters))
+}
+
+// ResolveEntryID returns the node b-tree entry referenced by the store entry ID.
+// The provider UID of the entry ID must be the record key (PR_RECORD_KEY) of the message store.
+//
+// References "[MS-PST] 2.4.3.2 Mapping between EntryID and NID".
+func (pff *PFF) ResolveEntryID(formatType string, entryID EntryID) (BTreeNodeEntry, error) {
+	if entryID.Type != EntryIDTypeStore {
+		return BTreeNodeEntry{}, errors.New("entry ID is not a store entry ID")
+	}
+
+	messageStore, err := pff.GetMessageStore(formatType)
+
+	if err != nil {
+		return BTreeNodeEntry{}, err
+	}
+
+	recordKey, err := messageStore.GetRecordKey()
+
+	if err != nil {
+		return BTreeNodeEntry{}, err
+	}
+
+	if !bytes.Equal(entryID.ProviderUID, recordKey) {
+		return BTreeNodeEntry{}, errors.New("entry ID does not belong to this message store")
+	}
+
+	return pff.FindNodeBTreeEntry(formatType, entryID.NodeIdentifier)
+}
@@ -0,0 +1,184 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"bytes"
+	"testing"
+)
+
+// getTestOneOffEntryID returns a one-off entry ID with the given flags and strings (already encoded).
+//
+// References "[MS-OXCDATA] 2.2.5.1 One-Off EntryID".
+func getTestOneOffEntryID(flags int, strings ...[]byte) []byte {
+	data := make([]byte, 4)
+
+	data = append(data, EntryIDProviderUIDOneOff...)
+	data = appendUint16(data, 0) // Version
+	data = appendUint16(data, flags)
+
+	for _, value := range strings {
+		data = append(data, value...)
+	}
+
+	return data
+}
+
+// getTestStoreEntryID returns a store entry ID of the node identifier.
+//
+// References "[MS-PST] 2.4.3.2 Mapping between EntryID and NID".
+func getTestStoreEntryID(recordKey []byte, nodeIdentifier int) []byte {
+	data := make([]byte, 4)
+
+	data = append(data, recordKey...)
+
+	return appendUint32(data, nodeIdentifier)
+}
+
+func TestGetEntryID(t *testing.T) {
+	recordKey := []byte{0x8e, 0xcc, 0xf9, 0xb4, 0x91, 0xd9, 0xfb, 0x4a, 0x9a, 0x9c, 0x3e, 0xea, 0xc1, 0xe3, 0x07, 0x48}
+
+	unicodeOneOff := getTestOneOffEntryID(OneOffEntryIDFlagUnicode|OneOffEntryIDFlagNoRichInfo,
+		appendUTF16(nil, "Jörg\x00"), appendUTF16(nil, "SMTP\x00"), appendUTF16(nil, "jorg@example.com\x00"))
+	ansiOneOff := getTestOneOffEntryID(0, []byte{0xc8, 0xe2, 0xe0, 0xed, 0x00}, []byte("SMTP\x00"), []byte("ivan@example.com\x00"))
+
+	addressBook := append(make([]byte, 4), EntryIDProviderUIDAddressBook...)
+	addressBook = appendUint32(addressBook, 1) // Version
+	addressBook = appendUint32(addressBook, 0) // Type (mail user)
+	addressBook = append(addressBook, "/o=Example/ou=First Administrative Group/cn=Recipients/cn=jorg\x00"...)
+
+	wrapped := append(make([]byte, 4), EntryIDProviderUIDWrapped...)
+	wrapped = append(wrapped, 0x83) // Wrapped type
+	wrapped = append(wrapped, getTestStoreEntryID(recordKey, 0x200024)...)
+
+	tests := []struct {
+		name           string
+		data           []byte
+		codePage       int
+		expected       EntryID
+		isErrorPresent bool
+	}{
+		{
+			name:     "store",
+			data:     getTestStoreEntryID(recordKey, 0x200024),
+			expected: EntryID{Type: EntryIDTypeStore, ProviderUID: recordKey, NodeIdentifier: 0x200024},
+		},
+		{
+			name:     "Unicode one-off",
+			data:     unicodeOneOff,
+			expected: EntryID{Type: EntryIDTypeOneOff, ProviderUID: EntryIDProviderUIDOneOff, DisplayName: "Jörg", AddressType: "SMTP", EmailAddress: "jorg@example.com"},
+		},
+		{
+			name:     "ANSI one-off",
+			data:     ansiOneOff,
+			codePage: 1251,
+			expected: EntryID{Type: EntryIDTypeOneOff, ProviderUID: EntryIDProviderUIDOneOff, DisplayName: "Иван", AddressType: "SMTP", EmailAddress: "ivan@example.com"},
+		},
+		{
+			name:     "address book",
+			data:     addressBook,
+			expected: EntryID{Type: EntryIDTypeAddressBook, ProviderUID: EntryIDProviderUIDAddressBook, X500DN: "/o=Example/ou=First Administrative Group/cn=Recipients/cn=jorg"},
+		},
+		{
+			name:     "wrapped",
+			data:     wrapped,
+			expected: EntryID{Type: EntryIDTypeWrapped, ProviderUID: EntryIDProviderUIDWrapped, WrappedType: 0x83},
+		},
+		{name: "truncated", data: getTestStoreEntryID(recordKey, 0x200024)[:16], isErrorPresent: true},
+		{name: "unterminated one-off string", data: ansiOneOff[:len(ansiOneOff)-1], isErrorPresent: true},
+		{name: "unknown type", data: append(getTestStoreEntryID(recordKey, 0x200024), 0x00), isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entryID, err := GetEntryID(test.data, test.codePage)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", entryID)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if entryID.Type != test.expected.Type || !bytes.Equal(entryID.ProviderUID, test.expected.ProviderUID) {
+				t.Fatalf("unexpected type %q or provider UID %x", entryID.Type, entryID.ProviderUID)
+			}
+
+			if entryID.NodeIdentifier != test.expected.NodeIdentifier || entryID.X500DN != test.expected.X500DN || entryID.WrappedType != test.expected.WrappedType {
+				t.Fatalf("unexpected entry ID %+v", entryID)
+			}
+
+			if entryID.DisplayName != test.expected.DisplayName || entryID.AddressType != test.expected.AddressType || entryID.EmailAddress != test.expected.EmailAddress {
+				t.Fatalf("unexpected one-off entry ID %q, %q, %q", entryID.DisplayName, entryID.AddressType, entryID.EmailAddress)
+			}
+
+			if entryID.Type == EntryIDTypeWrapped && (entryID.WrappedEntryID == nil || entryID.WrappedEntryID.NodeIdentifier != 0x200024) {
+				t.Fatalf("unexpected wrapped entry ID %+v", entryID.WrappedEntryID)
+			}
+		})
+	}
+}
+
+func TestResolveEntryID(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	messageStore, err := pst.GetMessageStore(FormatType32)
+
+	if err != nil {
+		t.Fatalf("failed to get message store: %s", err)
+	}
+
+	recordKey, err := messageStore.GetRecordKey()
+
+	if err != nil {
+		t.Fatalf("failed to get record key: %s", err)
+	}
+
+	tests := []struct {
+		name           string
+		recordKey      []byte
+		nodeIdentifier int
+		isErrorPresent bool
+	}{
+		{name: "message", recordKey: recordKey, nodeIdentifier: 0x200024},
+		{name: "root folder", recordKey: recordKey, nodeIdentifier: NodeBTreeIdentifierRootFolder},
+		// The message store node identifier is also a key in the node b-tree branch page.
+		{name: "branch key", recordKey: recordKey, nodeIdentifier: NodeBTreeIdentifierMessageStore},
+		{name: "other message store", recordKey: make([]byte, 16), nodeIdentifier: 0x200024, isErrorPresent: true},
+		{name: "missing node", recordKey: recordKey, nodeIdentifier: 0x1fffe4, isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entryID, err := GetEntryID(getTestStoreEntryID(test.recordKey, test.nodeIdentifier), 1252)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			nodeBTreeEntry, err := pst.ResolveEntryID(FormatType32, entryID)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", nodeBTreeEntry)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			// 32-bit node b-tree leaf entries are 16 bytes, branch entries 12 bytes.
+			if nodeBTreeEntry.Identifier != test.nodeIdentifier || len(nodeBTreeEntry.Data) != 16 {
+				t.Fatalf("unexpected node b-tree entry 0x%x (%d bytes)", nodeBTreeEntry.Identifier, len(nodeBTreeEntry.Data))
+			}
+		})
+	}
+}
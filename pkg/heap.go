@@ -0,0 +1,408 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// Constants for identifying the client of a heap-on-node.
+//
+// References "[MS-PST] 2.3.1.2 HNHDR".
+const (
+	HeapOnNodeSignature                 = 0xec
+	HeapOnNodeClientSignatureTable      = 0x7c
+	HeapOnNodeClientSignatureBTree      = 0xb5
+	HeapOnNodeClientSignatureProperties = 0xbc
+)
+
+// HeapOnNodeHeader represents the header of a heap-on-node stored at the start of the first data block of a node.
+type HeapOnNodeHeader struct {
+	PageMapOffset      int
+	Signature          int
+	ClientSignature    int
+	UserRootIdentifier int
+}
+
+// HeapOnNodeAllocation represents an allocation in the heap-on-node.
+type HeapOnNodeAllocation struct {
+	Identifier int
+	Offset     int
+	Size       int
+}
+
+// GetHeapOnNodeHeader returns the heap-on-node header of the first data block of a node.
+//
+// References "[MS-PST] 2.3.1.2 HNHDR".
+func GetHeapOnNodeHeader(data []byte) (HeapOnNodeHeader, error) {
+	if len(data) < 12 {
+		return HeapOnNodeHeader{}, errors.New("heap-on-node is too small")
+	}
+
+	heapOnNodeHeader := HeapOnNodeHeader{
+		PageMapOffset:      int(binary.LittleEndian.Uint16(data[0:2])),
+		Signature:          int(data[2]),
+		ClientSignature:    int(data[3]),
+		UserRootIdentifier: int(binary.LittleEndian.Uint32(data[4:8])),
+	}
+
+	if heapOnNodeHeader.Signature != HeapOnNodeSignature {
+		return HeapOnNodeHeader{}, errors.New("invalid heap-on-node signature")
+	}
+
+	return heapOnNodeHeader, nil
+}
+
+// GetHeapOnNodeAllocations returns the allocations of a heap-on-node block.
+//
+// References "[MS-PST] 2.3.1.5 HNPAGEMAP":
+// The page map contains the offsets of all allocations followed by the offset of the free space.
+func GetHeapOnNodeAllocations(data []byte, pageMapOffset int) ([]HeapOnNodeAllocation, error) {
+	if pageMapOffset+4 > len(data) {
+		return nil, errors.New("invalid heap-on-node page map offset")
+	}
+
+	allocationCount := int(binary.LittleEndian.Uint16(data[pageMapOffset : pageMapOffset+2]))
+
+	if pageMapOffset+4+(allocationCount+1)*2 > len(data) {
+		return nil, errors.New("invalid heap-on-node allocation count")
+	}
+
+	allocations := make([]HeapOnNodeAllocation, allocationCount)
+
+	for i := 0; i < allocationCount; i++ {
+		allocationOffset := pageMapOffset + 4 + i*2
+		start := int(binary.LittleEndian.Uint16(data[allocationOffset : allocationOffset+2]))
+		end := int(binary.LittleEndian.Uint16(data[allocationOffset+2 : allocationOffset+4]))
+
+		if end < start || end > len(data) {
+			return nil, errors.New("invalid heap-on-node allocation")
+		}
+
+		allocations[i] = HeapOnNodeAllocation{
+			// The heap identifier index is one-based and shifted past the 5 bits heap identifier type.
+			Identifier: (i + 1) << 5,
+			Offset:     start,
+			Size:       end - start,
+		}
+	}
+
+	return allocations, nil
+}
+
+// GetHeapOnNodeAllocation returns the data of the allocation of the heap identifier (HID) in the first heap-on-node block.
+//
+// References "[MS-PST] 2.3.1.1 HID".
+func GetHeapOnNodeAllocation(data []byte, heapIdentifier int) ([]byte, error) {
+	if heapIdentifier&0x1f != 0 {
+		return nil, errors.New("identifier is not a heap identifier")
+	}
+
+	if heapIdentifier>>16 != 0 {
+		return nil, errors.New("heap-on-node allocations in other blocks are not supported")
+	}
+
+	heapOnNodeHeader, err := GetHeapOnNodeHeader(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := GetHeapOnNodeAllocations(data, heapOnNodeHeader.PageMapOffset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allocationIndex := (heapIdentifier >> 5) & 0x7ff
+
+	if allocationIndex < 1 || allocationIndex > len(allocations) {
+		return nil, errors.New("invalid heap identifier index")
+	}
+
+	allocation := allocations[allocationIndex-1]
+
+	return data[allocation.Offset : allocation.Offset+allocation.Size], nil
+}
+
+// BTreeOnHeapHeader represents the header of a b-tree-on-heap (BTH).
+type BTreeOnHeapHeader struct {
+	Type           int
+	KeySize        int
+	EntrySize      int
+	IndexLevels    int
+	RootIdentifier int
+}
+
+// BTreeOnHeapRecord represents a leaf record of a b-tree-on-heap.
+type BTreeOnHeapRecord struct {
+	Key  []byte
+	Data []byte
+}
+
+// GetBTreeOnHeapHeader returns the b-tree-on-heap header stored in the allocation of the heap identifier.
+//
+// References "[MS-PST] 2.3.2.1 BTHHEADER".
+func GetBTreeOnHeapHeader(data []byte, heapIdentifier int) (BTreeOnHeapHeader, error) {
+	btreeOnHeapHeader, err := GetHeapOnNodeAllocation(data, heapIdentifier)
+
+	if err != nil {
+		return BTreeOnHeapHeader{}, err
+	}
+
+	if len(btreeOnHeapHeader) < 8 || btreeOnHeapHeader[0] != HeapOnNodeClientSignatureBTree {
+		return BTreeOnHeapHeader{}, errors.New("invalid b-tree-on-heap header")
+	}
+
+	return BTreeOnHeapHeader{
+		Type:           int(btreeOnHeapHeader[0]),
+		KeySize:        int(btreeOnHeapHeader[1]),
+		EntrySize:      int(btreeOnHeapHeader[2]),
+		IndexLevels:    int(btreeOnHeapHeader[3]),
+		RootIdentifier: int(binary.LittleEndian.Uint32(btreeOnHeapHeader[4:8])),
+	}, nil
+}
+
+// GetBTreeOnHeapRecords returns the leaf records of the b-tree-on-heap.
+//
+// References "[MS-PST] 2.3.2.2 Intermediate BTH (Index) Records" and "[MS-PST] 2.3.2.3 Leaf BTH (Data) Records":
+// Index records consist of the key and the heap identifier of the next level.
+func GetBTreeOnHeapRecords(data []byte, btreeOnHeapHeader BTreeOnHeapHeader) ([]BTreeOnHeapRecord, error) {
+	if btreeOnHeapHeader.RootIdentifier == 0 {
+		// Empty b-tree-on-heap
+		return nil, nil
+	}
+
+	return getBTreeOnHeapRecords(data, btreeOnHeapHeader, btreeOnHeapHeader.RootIdentifier, btreeOnHeapHeader.IndexLevels)
+}
+
+// getBTreeOnHeapRecords returns the leaf records of the b-tree-on-heap level.
+func getBTreeOnHeapRecords(data []byte, btreeOnHeapHeader BTreeOnHeapHeader, heapIdentifier int, level int) ([]BTreeOnHeapRecord, error) {
+	records, err := GetHeapOnNodeAllocation(data, heapIdentifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if level > 0 {
+		// Index records
+		recordSize := btreeOnHeapHeader.KeySize + 4
+
+		var leafRecords []BTreeOnHeapRecord
+
+		for i := 0; i+recordSize <= len(records); i += recordSize {
+			nextHeapIdentifier := int(binary.LittleEndian.Uint32(records[i+btreeOnHeapHeader.KeySize : i+recordSize]))
+
+			nextLeafRecords, err := getBTreeOnHeapRecords(data, btreeOnHeapHeader, nextHeapIdentifier, level-1)
+
+			if err != nil {
+				return nil, err
+			}
+
+			leafRecords = append(leafRecords, nextLeafRecords...)
+		}
+
+		return leafRecords, nil
+	}
+
+	// Leaf records
+	recordSize := btreeOnHeapHeader.KeySize + btreeOnHeapHeader.EntrySize
+
+	if recordSize == 0 {
+		return nil, errors.New("invalid b-tree-on-heap record size")
+	}
+
+	var leafRecords []BTreeOnHeapRecord
+
+	for i := 0; i+recordSize <= len(records); i += recordSize {
+		leafRecords = append(leafRecords, BTreeOnHeapRecord{
+			Key:  records[i : i+btreeOnHeapHeader.KeySize],
+			Data: records[i+btreeOnHeapHeader.KeySize : i+recordSize],
+		})
+	}
+
+	return leafRecords, nil
+}
+
+// Constants for identifying the property types of string properties.
+//
+// References "[MS-OXCDATA] 2.11.1 Property Data Types".
+const (
+	PropertyTypeString8 = 0x001e
+	PropertyTypeUnicode = 0x001f
+)
+
+// PropertyIDMessageClass is the property ID of the message class (PR_MESSAGE_CLASS).
+//
+// References "[MS-OXPROPS] PidTagMessageClass".
+const PropertyIDMessageClass = 0x001a
+
+// PropertyContextRecord represents a property of a property context (PC).
+// The value contains the data if it fits in 4 bytes, otherwise it is a heap identifier or local descriptor identifier (HNID).
+type PropertyContextRecord struct {
+	PropertyID   int
+	PropertyType int
+	Value        int
+}
+
+// GetPropertyContextRecords returns the property records of the property context stored in the first data block of a node.
+//
+// References "[MS-PST] 2.3.3 Property Context (PC)" and "[MS-PST] 2.3.3.3 PC BTH Record".
+func GetPropertyContextRecords(data []byte) ([]PropertyContextRecord, error) {
+	heapOnNodeHeader, err := GetHeapOnNodeHeader(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if heapOnNodeHeader.ClientSignature != HeapOnNodeClientSignatureProperties {
+		return nil, errors.New("heap-on-node is not a property context")
+	}
+
+	btreeOnHeapHeader, err := GetBTreeOnHeapHeader(data, heapOnNodeHeader.UserRootIdentifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if btreeOnHeapHeader.KeySize != 2 || btreeOnHeapHeader.EntrySize != 6 {
+		return nil, errors.New("invalid property context b-tree-on-heap")
+	}
+
+	btreeOnHeapRecords, err := GetBTreeOnHeapRecords(data, btreeOnHeapHeader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	propertyContextRecords := make([]PropertyContextRecord, len(btreeOnHeapRecords))
+
+	for i, btreeOnHeapRecord := range btreeOnHeapRecords {
+		propertyContextRecords[i] = PropertyContextRecord{
+			PropertyID:   int(binary.LittleEndian.Uint16(btreeOnHeapRecord.Key)),
+			PropertyType: int(binary.LittleEndian.Uint16(btreeOnHeapRecord.Data[0:2])),
+			Value:        int(binary.LittleEndian.Uint32(btreeOnHeapRecord.Data[2:6])),
+		}
+	}
+
+	return propertyContextRecords, nil
+}
+
+// PropertyContext represents the property context of a node, stored in its first data block.
+type PropertyContext struct {
+	Data    []byte
+	Records []PropertyContextRecord
+}
+
+// GetPropertyContext returns the property context of the node.
+// Property contexts stored in a data tree (multiple blocks) are not supported.
+func (pff *PFF) GetPropertyContext(formatType string, nodeIdentifier int) (PropertyContext, error) {
+	data, err := pff.GetNodeData(formatType, nodeIdentifier)
+
+	if err != nil {
+		return PropertyContext{}, err
+	}
+
+	return newPropertyContext(data)
+}
+
+// GetPropertyContextOfNodeEntry returns the property context of the node b-tree entry.
+// The block b-tree entries (see GetBTreeNodeEntryMap) are used instead of searching the block b-tree,
+// which is faster when reading the property contexts of many nodes.
+func (pff *PFF) GetPropertyContextOfNodeEntry(formatType string, encryptionType string, nodeBTreeEntry BTreeNodeEntry, blockBTreeEntries map[int]BTreeNodeEntry) (PropertyContext, error) {
+	blockIdentifier, err := getNodeBlockIdentifier(formatType, nodeBTreeEntry)
+
+	if err != nil {
+		return PropertyContext{}, err
+	}
+
+	blockBTreeEntry, ok := blockBTreeEntries[blockIdentifier]
+
+	if !ok {
+		return PropertyContext{}, errors.New("block not found")
+	}
+
+	data, err := pff.GetBlockData(formatType, encryptionType, blockBTreeEntry)
+
+	if err != nil {
+		return PropertyContext{}, err
+	}
+
+	return newPropertyContext(data)
+}
+
+// newPropertyContext returns the property context stored in the data of a node.
+func newPropertyContext(data []byte) (PropertyContext, error) {
+	propertyContextRecords, err := GetPropertyContextRecords(data)
+
+	if err != nil {
+		return PropertyContext{}, err
+	}
+
+	return PropertyContext{
+		Data:    data,
+		Records: propertyContextRecords,
+	}, nil
+}
+
+// GetRecord returns the record of the property ID.
+func (propertyContext *PropertyContext) GetRecord(propertyID int) (PropertyContextRecord, bool) {
+	for _, propertyContextRecord := range propertyContext.Records {
+		if propertyContextRecord.PropertyID == propertyID {
+			return propertyContextRecord, true
+		}
+	}
+
+	return PropertyContextRecord{}, false
+}
+
+// GetData returns the data of a variable size property, which is stored in the heap-on-node.
+//
+// References "[MS-PST] 2.3.3.3 PC BTH Record":
+// Values stored in local descriptors (subnodes) are not supported.
+func (propertyContext *PropertyContext) GetData(propertyID int) ([]byte, error) {
+	propertyContextRecord, ok := propertyContext.GetRecord(propertyID)
+
+	if !ok {
+		return nil, errors.New("property not found")
+	}
+
+	if propertyContextRecord.Value == 0 {
+		// Empty value
+		return nil, nil
+	} else if propertyContextRecord.Value&0x1f != 0 {
+		return nil, errors.New("properties stored in local descriptors are not supported")
+	}
+
+	return GetHeapOnNodeAllocation(propertyContext.Data, propertyContextRecord.Value)
+}
+
+// GetString returns the value of a string (PT_STRING8 or PT_UNICODE) property.
+// The code page (see StringDecoder.GetCodePage) is used for PT_STRING8 values, falling back to Windows-1252 if unsupported.
+func (propertyContext *PropertyContext) GetString(propertyID int, codePage int) (string, error) {
+	propertyContextRecord, ok := propertyContext.GetRecord(propertyID)
+
+	if !ok {
+		return "", errors.New("property not found")
+	}
+
+	data, err := propertyContext.GetData(propertyID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if propertyContextRecord.PropertyType == PropertyTypeUnicode {
+		reader := newBlobReader(data)
+
+		return strings.TrimRight(reader.readUTF16(len(data)/2), "\x00"), nil
+	} else if propertyContextRecord.PropertyType == PropertyTypeString8 {
+		stringDecoder := NewStringDecoder(1252)
+
+		return stringDecoder.DecodeString8(data, codePage)
+	} else {
+		return "", errors.New("property is not a string")
+	}
+}
@@ -0,0 +1,205 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// iCalendarWeekDays contains the iCalendar week days in order of the recurrence pattern day of week bits (Sunday first).
+var iCalendarWeekDays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// getICalendarWeekDays returns the iCalendar week days of the day of week mask.
+func getICalendarWeekDays(dayOfWeekMask int) string {
+	var weekDays []string
+
+	for i, weekDay := range iCalendarWeekDays {
+		if dayOfWeekMask&(1<<i) != 0 {
+			weekDays = append(weekDays, weekDay)
+		}
+	}
+
+	return strings.Join(weekDays, ",")
+}
+
+// getICalendarWeekOfMonth returns the iCalendar week of month, where the fifth week means the last week.
+func getICalendarWeekOfMonth(weekOfMonth int) int {
+	if weekOfMonth == 5 {
+		return -1
+	}
+
+	return weekOfMonth
+}
+
+// GetICalendarRule returns the iCalendar RRULE value of the recurrence pattern.
+//
+// References "[MS-OXOCAL] RecurrencePattern Structure" and "RFC 5545 3.3.10. Recurrence Rule":
+// The period is in minutes for daily, in weeks for weekly and in months for monthly and yearly recurrences.
+// UNTIL must have the same value type as DTSTART, so it is a DATE for all-day appointments and otherwise the
+// DATE-TIME of the last occurrence (the end date plus the start time offset in minutes).
+// The location is the time zone of DTSTART (TZID) in which case UNTIL is converted to UTC,
+// a nil location results in a floating (local) time.
+func (recurrencePattern *RecurrencePattern) GetICalendarRule(startTimeOffset int, isAllDay bool, location *time.Location) (string, error) {
+	var rule []string
+
+	switch recurrencePattern.PatternType {
+	case RecurrencePatternTypeDay:
+		rule = append(rule, "FREQ=DAILY", fmt.Sprintf("INTERVAL=%d", recurrencePattern.Period/1440))
+	case RecurrencePatternTypeWeek:
+		rule = append(rule, "FREQ=WEEKLY", fmt.Sprintf("INTERVAL=%d", recurrencePattern.Period))
+		rule = append(rule, "BYDAY="+getICalendarWeekDays(recurrencePattern.DayOfWeekMask))
+
+		if recurrencePattern.FirstDayOfWeek >= 0 && recurrencePattern.FirstDayOfWeek < len(iCalendarWeekDays) {
+			rule = append(rule, "WKST="+iCalendarWeekDays[recurrencePattern.FirstDayOfWeek])
+		}
+	case RecurrencePatternTypeMonth, RecurrencePatternTypeMonthNth, RecurrencePatternTypeMonthEnd:
+		if recurrencePattern.Frequency == RecurrenceFrequencyYearly {
+			rule = append(rule, "FREQ=YEARLY", fmt.Sprintf("INTERVAL=%d", recurrencePattern.Period/12))
+			rule = append(rule, fmt.Sprintf("BYMONTH=%d", int(recurrencePattern.StartDate.Month())))
+		} else {
+			rule = append(rule, "FREQ=MONTHLY", fmt.Sprintf("INTERVAL=%d", recurrencePattern.Period))
+		}
+
+		if recurrencePattern.PatternType == RecurrencePatternTypeMonth {
+			rule = append(rule, fmt.Sprintf("BYMONTHDAY=%d", recurrencePattern.DayOfMonth))
+		} else if recurrencePattern.PatternType == RecurrencePatternTypeMonthEnd {
+			rule = append(rule, "BYMONTHDAY=-1")
+		} else {
+			rule = append(rule, "BYDAY="+getICalendarWeekDays(recurrencePattern.DayOfWeekMask))
+			rule = append(rule, fmt.Sprintf("BYSETPOS=%d", getICalendarWeekOfMonth(recurrencePattern.WeekOfMonth)))
+		}
+	default:
+		return "", errors.New("unsupported recurrence pattern type for iCalendar")
+	}
+
+	switch recurrencePattern.EndType {
+	case RecurrenceEndTypeAfterDate:
+		rule = append(rule, "UNTIL="+getICalendarUntil(recurrencePattern.EndDate, startTimeOffset, isAllDay, location))
+	case RecurrenceEndTypeAfterOccurrenceCount:
+		rule = append(rule, fmt.Sprintf("COUNT=%d", recurrencePattern.OccurrenceCount))
+	}
+
+	return strings.Join(rule, ";"), nil
+}
+
+// getICalendarUntil returns the UNTIL value of the last occurrence date, see GetICalendarRule.
+func getICalendarUntil(endDate time.Time, startTimeOffset int, isAllDay bool, location *time.Location) string {
+	if isAllDay {
+		return endDate.Format("20060102")
+	}
+
+	lastOccurrence := endDate.Add(time.Duration(startTimeOffset) * time.Minute)
+
+	if location == nil {
+		return lastOccurrence.Format("20060102T150405")
+	}
+
+	return getLocalTime(lastOccurrence, location).UTC().Format("20060102T150405Z")
+}
+
+// getLocalTime returns the time in the location of the wall-clock time.
+// Recurrence blobs store local (wall-clock) times as if they were UTC.
+func getLocalTime(wallClockTime time.Time, location *time.Location) time.Time {
+	return time.Date(wallClockTime.Year(), wallClockTime.Month(), wallClockTime.Day(), wallClockTime.Hour(), wallClockTime.Minute(), wallClockTime.Second(), 0, location)
+}
+
+// GetICalendarRule returns the iCalendar RRULE value of the appointment recurrence, see RecurrencePattern.GetICalendarRule.
+func (appointmentRecurrencePattern *AppointmentRecurrencePattern) GetICalendarRule(isAllDay bool, location *time.Location) (string, error) {
+	return appointmentRecurrencePattern.RecurrencePattern.GetICalendarRule(appointmentRecurrencePattern.StartTimeOffset, isAllDay, location)
+}
+
+// GetDeletedOccurrences returns the start times of the deleted occurrences, used for iCalendar EXDATE values.
+// The location is the time zone of the appointment, the recurrence blob only contains local (wall-clock) times.
+// A nil location returns floating (wall-clock) times without a time zone, like GetICalendarRule;
+// these are stored as UTC and must be formatted without the "Z" suffix.
+//
+// References "[MS-OXOCAL] RecurrencePattern Structure" and "[MS-OXOCAL] ExceptionInfo Structure":
+// The deleted instance dates contain the original dates of both deleted and modified occurrences.
+// Modified occurrences are excluded by the original start date of their exception, these are exported as RECURRENCE-ID instead.
+// The modified instance dates contain the new dates of modified occurrences and can not be used for this.
+func (appointmentRecurrencePattern *AppointmentRecurrencePattern) GetDeletedOccurrences(location *time.Location) []time.Time {
+	modifiedOriginalDates := make(map[time.Time]bool)
+
+	for _, exception := range appointmentRecurrencePattern.Exceptions {
+		originalStartDate := exception.OriginalStartDate
+
+		modifiedOriginalDates[time.Date(originalStartDate.Year(), originalStartDate.Month(), originalStartDate.Day(), 0, 0, 0, 0, time.UTC)] = true
+	}
+
+	var deletedOccurrences []time.Time
+
+	for _, deletedInstanceDate := range appointmentRecurrencePattern.RecurrencePattern.DeletedInstanceDates {
+		if modifiedOriginalDates[time.Date(deletedInstanceDate.Year(), deletedInstanceDate.Month(), deletedInstanceDate.Day(), 0, 0, 0, 0, time.UTC)] {
+			continue
+		}
+
+		deletedOccurrence := deletedInstanceDate.Add(time.Duration(appointmentRecurrencePattern.StartTimeOffset) * time.Minute)
+
+		if location == nil {
+			deletedOccurrences = append(deletedOccurrences, deletedOccurrence)
+		} else {
+			deletedOccurrences = append(deletedOccurrences, getLocalTime(deletedOccurrence, location))
+		}
+	}
+
+	return deletedOccurrences
+}
+
+// getICalendarUTCOffset returns the iCalendar UTC offset of the bias (in minutes).
+func getICalendarUTCOffset(bias int) string {
+	offset := -bias
+	sign := "+"
+
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+
+	return fmt.Sprintf("%s%02d%02d", sign, offset/60, offset%60)
+}
+
+// getICalendarTimeZoneObservance returns the STANDARD or DAYLIGHT component of a time zone.
+func getICalendarTimeZoneObservance(name string, transition SystemTime, offsetFrom string, offsetTo string) string {
+	var observance strings.Builder
+
+	observance.WriteString("BEGIN:" + name + "\r\n")
+	observance.WriteString(fmt.Sprintf("DTSTART:16010101T%02d%02d%02d\r\n", transition.Hour, transition.Minute, transition.Second))
+	observance.WriteString("TZOFFSETFROM:" + offsetFrom + "\r\n")
+	observance.WriteString("TZOFFSETTO:" + offsetTo + "\r\n")
+
+	if transition.Month != 0 {
+		observance.WriteString(fmt.Sprintf("RRULE:FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s\r\n", transition.Month, getICalendarWeekOfMonth(transition.Day), iCalendarWeekDays[transition.DayOfWeek%7]))
+	}
+
+	observance.WriteString("END:" + name + "\r\n")
+
+	return observance.String()
+}
+
+// GetICalendarTimeZone returns the iCalendar VTIMEZONE component of the time zone rule.
+//
+// References "[MS-OXOCAL] TZRule Structure" and "RFC 5545 3.6.5. Time Zone Component":
+// A time zone rule without a standard date month does not observe daylight saving time.
+func (timeZoneRule *TimeZoneRule) GetICalendarTimeZone(timeZoneID string) string {
+	var timeZone strings.Builder
+
+	standardOffset := getICalendarUTCOffset(timeZoneRule.Bias + timeZoneRule.StandardBias)
+	daylightOffset := getICalendarUTCOffset(timeZoneRule.Bias + timeZoneRule.DaylightBias)
+
+	timeZone.WriteString("BEGIN:VTIMEZONE\r\n")
+	timeZone.WriteString("TZID:" + timeZoneID + "\r\n")
+
+	if timeZoneRule.StandardDate.Month == 0 {
+		timeZone.WriteString(getICalendarTimeZoneObservance("STANDARD", SystemTime{}, standardOffset, standardOffset))
+	} else {
+		timeZone.WriteString(getICalendarTimeZoneObservance("STANDARD", timeZoneRule.StandardDate, daylightOffset, standardOffset))
+		timeZone.WriteString(getICalendarTimeZoneObservance("DAYLIGHT", timeZoneRule.DaylightDate, standardOffset, daylightOffset))
+	}
+
+	timeZone.WriteString("END:VTIMEZONE\r\n")
+
+	return timeZone.String()
+}
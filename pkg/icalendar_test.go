@@ -0,0 +1,149 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetICalendarRule(t *testing.T) {
+	centralEuropeanTime := time.FixedZone("CET", 3600)
+
+	weekly := RecurrencePattern{
+		Frequency:       RecurrenceFrequencyWeekly,
+		PatternType:     RecurrencePatternTypeWeek,
+		Period:          1,
+		DayOfWeekMask:   0x02,
+		EndType:         RecurrenceEndTypeAfterOccurrenceCount,
+		OccurrenceCount: 5,
+		FirstDayOfWeek:  1,
+	}
+
+	untilDate := weekly
+	untilDate.EndType = RecurrenceEndTypeAfterDate
+	untilDate.EndDate = time.Date(2021, 3, 29, 0, 0, 0, 0, time.UTC)
+
+	monthNth := RecurrencePattern{
+		Frequency:     RecurrenceFrequencyMonthly,
+		PatternType:   RecurrencePatternTypeMonthNth,
+		Period:        1,
+		DayOfWeekMask: 0x3e,
+		WeekOfMonth:   5,
+		EndType:       RecurrenceEndTypeNeverEnd,
+	}
+
+	tests := []struct {
+		name              string
+		recurrencePattern RecurrencePattern
+		isAllDay          bool
+		location          *time.Location
+		expected          string
+	}{
+		{name: "weekly count", recurrencePattern: weekly, location: centralEuropeanTime, expected: "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO;WKST=MO;COUNT=5"},
+		{name: "until with time zone", recurrencePattern: untilDate, location: centralEuropeanTime, expected: "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO;WKST=MO;UNTIL=20210329T080000Z"},
+		{name: "until floating", recurrencePattern: untilDate, expected: "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO;WKST=MO;UNTIL=20210329T090000"},
+		{name: "until all-day", recurrencePattern: untilDate, isAllDay: true, expected: "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO;WKST=MO;UNTIL=20210329"},
+		{name: "last weekday of the month", recurrencePattern: monthNth, expected: "FREQ=MONTHLY;INTERVAL=1;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := test.recurrencePattern.GetICalendarRule(540, test.isAllDay, test.location)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if rule != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, rule)
+			}
+		})
+	}
+}
+
+func TestGetDeletedOccurrences(t *testing.T) {
+	appointmentRecurrencePattern, err := GetAppointmentRecurrencePattern(getTestAppointmentRecurrencePattern(), 1252)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	centralEuropeanTime := time.FixedZone("CET", 3600)
+
+	// The occurrence of March 15 is moved to March 16 and is excluded by its original start date.
+	deletedOccurrences := appointmentRecurrencePattern.GetDeletedOccurrences(centralEuropeanTime)
+
+	expected := time.Date(2021, 3, 8, 8, 0, 0, 0, time.UTC)
+
+	if len(deletedOccurrences) != 1 || !deletedOccurrences[0].Equal(expected) {
+		t.Fatalf("expected [%s], got %v", expected, deletedOccurrences)
+	}
+
+	// Floating times are the wall-clock times of the recurrence blob.
+	floatingDeletedOccurrences := appointmentRecurrencePattern.GetDeletedOccurrences(nil)
+
+	expectedFloating := time.Date(2021, 3, 8, 9, 0, 0, 0, time.UTC)
+
+	if len(floatingDeletedOccurrences) != 1 || !floatingDeletedOccurrences[0].Equal(expectedFloating) {
+		t.Fatalf("expected [%s], got %v", expectedFloating, floatingDeletedOccurrences)
+	}
+}
+
+func TestGetICalendarTimeZone(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeZoneID   string
+		timeZoneRule TimeZoneRule
+		expected     string
+	}{
+		{
+			name:       "daylight saving time",
+			timeZoneID: "W. Europe Standard Time",
+			timeZoneRule: TimeZoneRule{
+				Bias:         -60,
+				DaylightBias: -60,
+				StandardDate: SystemTime{Month: 10, DayOfWeek: 0, Day: 5, Hour: 3},
+				DaylightDate: SystemTime{Month: 3, DayOfWeek: 0, Day: 5, Hour: 2},
+			},
+			expected: "BEGIN:VTIMEZONE\r\n" +
+				"TZID:W. Europe Standard Time\r\n" +
+				"BEGIN:STANDARD\r\n" +
+				"DTSTART:16010101T030000\r\n" +
+				"TZOFFSETFROM:+0200\r\n" +
+				"TZOFFSETTO:+0100\r\n" +
+				"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\n" +
+				"END:STANDARD\r\n" +
+				"BEGIN:DAYLIGHT\r\n" +
+				"DTSTART:16010101T020000\r\n" +
+				"TZOFFSETFROM:+0100\r\n" +
+				"TZOFFSETTO:+0200\r\n" +
+				"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\n" +
+				"END:DAYLIGHT\r\n" +
+				"END:VTIMEZONE\r\n",
+		},
+		{
+			name:         "no daylight saving time",
+			timeZoneID:   "Tokyo Standard Time",
+			timeZoneRule: TimeZoneRule{Bias: -540},
+			expected: "BEGIN:VTIMEZONE\r\n" +
+				"TZID:Tokyo Standard Time\r\n" +
+				"BEGIN:STANDARD\r\n" +
+				"DTSTART:16010101T000000\r\n" +
+				"TZOFFSETFROM:+0900\r\n" +
+				"TZOFFSETTO:+0900\r\n" +
+				"END:STANDARD\r\n" +
+				"END:VTIMEZONE\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			timeZone := test.timeZoneRule.GetICalendarTimeZone(test.timeZoneID)
+
+			if timeZone != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, timeZone)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Constants for identifying meeting related message types.
+//
+// References "[MS-OXOCAL] Meeting Request Object", "[MS-OXOCAL] Meeting Response Object" and "[MS-OXOCAL] Meeting Update Object".
+const (
+	MeetingMessageTypeNone              = "none"
+	MeetingMessageTypeRequest           = "request"
+	MeetingMessageTypeResponsePositive  = "response-positive"
+	MeetingMessageTypeResponseNegative  = "response-negative"
+	MeetingMessageTypeResponseTentative = "response-tentative"
+	MeetingMessageTypeCanceled          = "canceled"
+	MeetingMessageTypeForward           = "forward-notification"
+	MeetingMessageTypeRecall            = "recall"
+)
+
+// meetingMessageClasses maps message classes (lower case) to their meeting message type.
+var meetingMessageClasses = map[string]string{
+	"ipm.schedule.meeting.request":              MeetingMessageTypeRequest,
+	"ipm.schedule.meeting.resp.pos":             MeetingMessageTypeResponsePositive,
+	"ipm.schedule.meeting.resp.neg":             MeetingMessageTypeResponseNegative,
+	"ipm.schedule.meeting.resp.tent":            MeetingMessageTypeResponseTentative,
+	"ipm.schedule.meeting.canceled":             MeetingMessageTypeCanceled,
+	"ipm.schedule.meeting.notification.forward": MeetingMessageTypeForward,
+	"ipm.outlook.recall":                        MeetingMessageTypeRecall,
+}
+
+// GetMeetingMessageType returns the meeting message type of the message class (PR_MESSAGE_CLASS).
+// Message classes are case-insensitive and may be extended with a suffix, for example "IPM.Schedule.Meeting.Request.Custom".
+func GetMeetingMessageType(messageClass string) string {
+	messageClass = strings.ToLower(messageClass)
+
+	for {
+		if meetingMessageType, ok := meetingMessageClasses[messageClass]; ok {
+			return meetingMessageType
+		}
+
+		separatorIndex := strings.LastIndex(messageClass, ".")
+
+		if separatorIndex == -1 {
+			return MeetingMessageTypeNone
+		}
+
+		messageClass = messageClass[:separatorIndex]
+	}
+}
+
+// globalObjectIDArrayID is the byte array identifier every global object ID starts with.
+var globalObjectIDArrayID = []byte{0x04, 0x00, 0x00, 0x00, 0x82, 0x00, 0xe0, 0x00, 0x74, 0xc5, 0xb7, 0x10, 0x1a, 0x82, 0xe0, 0x08}
+
+// globalObjectIDICalendarPrefix is the prefix of global object ID data which contains an iCalendar UID.
+var globalObjectIDICalendarPrefix = []byte("vCal-Uid\x01\x00\x00\x00")
+
+// GlobalObjectID represents the identifier of a meeting (PidLidGlobalObjectId or PidLidCleanGlobalObjectId).
+type GlobalObjectID struct {
+	Data          []byte
+	InstanceYear  int
+	InstanceMonth int
+	InstanceDay   int
+	CreationTime  time.Time
+	ObjectData    []byte
+}
+
+// GetGlobalObjectID returns the global object ID of a PidLidGlobalObjectId or PidLidCleanGlobalObjectId value.
+//
+// References "[MS-OXOCAL] PidLidGlobalObjectId":
+// The instance date (year, month and day) identifies an exception of a recurring meeting and is zero otherwise.
+func GetGlobalObjectID(data []byte) (GlobalObjectID, error) {
+	reader := newBlobReader(data)
+
+	if !bytes.Equal(reader.read(16), globalObjectIDArrayID) {
+		return GlobalObjectID{}, errors.New("invalid global object ID")
+	}
+
+	globalObjectID := GlobalObjectID{
+		Data: data,
+	}
+
+	yearHigh := reader.readUint8()
+	yearLow := reader.readUint8()
+
+	globalObjectID.InstanceYear = yearHigh<<8 | yearLow
+	globalObjectID.InstanceMonth = reader.readUint8()
+	globalObjectID.InstanceDay = reader.readUint8()
+	globalObjectID.CreationTime = reader.readFileTime()
+	reader.read(8) // Reserved
+	globalObjectID.ObjectData = reader.read(reader.readUint32())
+
+	if reader.Err != nil {
+		return GlobalObjectID{}, reader.Err
+	}
+
+	return globalObjectID, nil
+}
+
+// IsException returns true if the global object ID identifies an exception of a recurring meeting.
+func (globalObjectID *GlobalObjectID) IsException() bool {
+	return globalObjectID.InstanceYear != 0 || globalObjectID.InstanceMonth != 0 || globalObjectID.InstanceDay != 0
+}
+
+// GetCleanGlobalObjectID returns the global object ID without the instance date (PidLidCleanGlobalObjectId).
+// Meeting requests, responses and calendar items of the same meeting share the clean global object ID.
+// Data which is too small to contain the instance date (for example a zero value) is returned unchanged.
+func (globalObjectID *GlobalObjectID) GetCleanGlobalObjectID() []byte {
+	cleanGlobalObjectID := make([]byte, len(globalObjectID.Data))
+
+	copy(cleanGlobalObjectID, globalObjectID.Data)
+
+	if len(cleanGlobalObjectID) >= 20 {
+		copy(cleanGlobalObjectID[16:20], []byte{0, 0, 0, 0})
+	}
+
+	return cleanGlobalObjectID
+}
+
+// GetICalendarUID returns the iCalendar UID of the meeting.
+//
+// References "[MS-OXCICAL] UID Property":
+// The UID is embedded in the global object ID data if the meeting originated from iCalendar,
+// otherwise the UID is the hexadecimal clean global object ID.
+func (globalObjectID *GlobalObjectID) GetICalendarUID() string {
+	if bytes.HasPrefix(globalObjectID.ObjectData, globalObjectIDICalendarPrefix) {
+		return string(bytes.TrimRight(globalObjectID.ObjectData[len(globalObjectIDICalendarPrefix):], "\x00"))
+	}
+
+	return strings.ToUpper(hex.EncodeToString(globalObjectID.GetCleanGlobalObjectID()))
+}
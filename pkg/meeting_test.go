@@ -0,0 +1,124 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestGetMeetingMessageType(t *testing.T) {
+	tests := []struct {
+		messageClass string
+		expected     string
+	}{
+		{messageClass: "IPM.Schedule.Meeting.Request", expected: MeetingMessageTypeRequest},
+		{messageClass: "ipm.schedule.meeting.resp.pos", expected: MeetingMessageTypeResponsePositive},
+		{messageClass: "IPM.Schedule.Meeting.Canceled.Custom", expected: MeetingMessageTypeCanceled},
+		{messageClass: "IPM.Note", expected: MeetingMessageTypeNone},
+		{messageClass: "", expected: MeetingMessageTypeNone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.messageClass, func(t *testing.T) {
+			if meetingMessageType := GetMeetingMessageType(test.messageClass); meetingMessageType != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, meetingMessageType)
+			}
+		})
+	}
+}
+
+// getTestGlobalObjectID returns a global object ID of the exception on March 15, 2021 created on March 1, 2021.
+//
+// References "[MS-OXOCAL] PidLidGlobalObjectId".
+func getTestGlobalObjectID(objectData []byte) []byte {
+	data := append([]byte{}, globalObjectIDArrayID...)
+
+	data = append(data, 0x07, 0xe5, 0x03, 0x0f)                         // Instance date (year high and low byte, month, day)
+	data = append(data, 0x00, 0xc0, 0x94, 0xd2, 0x2d, 0x0e, 0xd7, 0x01) // Creation time
+	data = append(data, make([]byte, 8)...)                             // Reserved
+	data = appendUint32(data, len(objectData))
+
+	return append(data, objectData...)
+}
+
+func TestGetGlobalObjectID(t *testing.T) {
+	objectData := []byte{0x10, 0x00, 0x00, 0x00, 0x8a, 0x1f, 0x3c, 0x5e, 0x27, 0x1d, 0x4a, 0x4b, 0x9e, 0x3f, 0x52, 0x11}
+	iCalendarObjectData := append([]byte("vCal-Uid\x01\x00\x00\x00"), "040000008200E00074C5B7101A82E008@example.com\x00"...)
+
+	tests := []struct {
+		name           string
+		data           []byte
+		expectedUID    string
+		isErrorPresent bool
+	}{
+		{
+			name:        "Outlook",
+			data:        getTestGlobalObjectID(objectData),
+			expectedUID: "040000008200E00074C5B7101A82E0080000000000C094D22D0ED701000000000000000010000000100000008A1F3C5E271D4A4B9E3F5211",
+		},
+		{
+			name:        "iCalendar",
+			data:        getTestGlobalObjectID(iCalendarObjectData),
+			expectedUID: "040000008200E00074C5B7101A82E008@example.com",
+		},
+		{name: "truncated", data: getTestGlobalObjectID(objectData)[:50], isErrorPresent: true},
+		{name: "invalid array identifier", data: append([]byte{0x05}, getTestGlobalObjectID(objectData)[1:]...), isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			globalObjectID, err := GetGlobalObjectID(test.data)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", globalObjectID)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !globalObjectID.IsException() || globalObjectID.InstanceYear != 2021 || globalObjectID.InstanceMonth != 3 || globalObjectID.InstanceDay != 15 {
+				t.Fatalf("unexpected instance date %d-%d-%d", globalObjectID.InstanceYear, globalObjectID.InstanceMonth, globalObjectID.InstanceDay)
+			}
+
+			if expected := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC); !globalObjectID.CreationTime.Equal(expected) {
+				t.Fatalf("expected creation time %s, got %s", expected, globalObjectID.CreationTime)
+			}
+
+			cleanGlobalObjectID := globalObjectID.GetCleanGlobalObjectID()
+
+			if !bytes.Equal(cleanGlobalObjectID[16:20], []byte{0, 0, 0, 0}) || !bytes.Equal(globalObjectID.Data[16:20], []byte{0x07, 0xe5, 0x03, 0x0f}) {
+				t.Fatalf("unexpected clean global object ID %x", cleanGlobalObjectID)
+			}
+
+			if uid := globalObjectID.GetICalendarUID(); uid != test.expectedUID {
+				t.Fatalf("expected UID %q, got %q", test.expectedUID, uid)
+			}
+		})
+	}
+}
+
+func TestGetICalendarUIDWithoutInstanceDate(t *testing.T) {
+	tests := []struct {
+		name           string
+		globalObjectID GlobalObjectID
+		expected       string
+	}{
+		{name: "zero value", globalObjectID: GlobalObjectID{}, expected: ""},
+		{name: "truncated", globalObjectID: GlobalObjectID{Data: globalObjectIDArrayID[:4]}, expected: "04000000"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if uid := test.globalObjectID.GetICalendarUID(); uid != test.expected {
+				t.Fatalf("expected UID %q, got %q", test.expected, uid)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+// NodeBTreeIdentifierMessageStore is the node identifier of the message store.
+//
+// References "[MS-PST] 2.4.1 Special Internal NIDs".
+const NodeBTreeIdentifierMessageStore = 33
+
+// Constants for identifying message store properties.
+//
+// References "[MS-PST] 2.4.3.1 Minimum Set of Required Properties".
+const (
+	PropertyIDRecordKey   = 0x0ff9
+	PropertyIDDisplayName = 0x3001
+	PropertyIDPassword    = 0x67ff
+)
+
+// MessageStore represents the message store, which contains the properties of the Personal Folder File.
+type MessageStore struct {
+	PropertyContext PropertyContext
+}
+
+// GetMessageStore returns the message store.
+func (pff *PFF) GetMessageStore(formatType string) (MessageStore, error) {
+	propertyContext, err := pff.GetPropertyContext(formatType, NodeBTreeIdentifierMessageStore)
+
+	if err != nil {
+		return MessageStore{}, err
+	}
+
+	return MessageStore{
+		PropertyContext: propertyContext,
+	}, nil
+}
+
+// GetRecordKey returns the record key (PR_RECORD_KEY) of the message store.
+// The record key is the provider UID of the store entry IDs in this message store.
+func (messageStore *MessageStore) GetRecordKey() ([]byte, error) {
+	return messageStore.PropertyContext.GetData(PropertyIDRecordKey)
+}
+
+// GetDisplayName returns the display name (PR_DISPLAY_NAME) of the message store.
+// The code page is used if the display name is a PT_STRING8 value (ANSI/32-bit PFF files).
+func (messageStore *MessageStore) GetDisplayName(codePage int) (string, error) {
+	return messageStore.PropertyContext.GetString(PropertyIDDisplayName, codePage)
+}
+
+// IsPasswordProtected returns true if the message store has a password.
+//
+// References "[MS-PST] PST Password Security":
+// PidTagPstPassword contains the CRC-32 of the password, or zero if there is no password.
+func (messageStore *MessageStore) IsPasswordProtected() bool {
+	propertyContextRecord, ok := messageStore.PropertyContext.GetRecord(PropertyIDPassword)
+
+	return ok && propertyContextRecord.Value != 0
+}
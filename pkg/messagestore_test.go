@@ -0,0 +1,155 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"testing"
+)
+
+func TestGetMessageStore(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	messageStore, err := pst.GetMessageStore(FormatType32)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	displayName, err := messageStore.GetDisplayName(1252)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if displayName != "Personal Folders" {
+		t.Fatalf("expected display name %q, got %q", "Personal Folders", displayName)
+	}
+
+	if messageStore.IsPasswordProtected() {
+		t.Fatalf("expected no password")
+	}
+
+	if recordKey, err := messageStore.GetRecordKey(); err != nil || len(recordKey) != 16 {
+		t.Fatalf("expected a 16 bytes record key, got %x (%v)", recordKey, err)
+	}
+
+	if _, err := messageStore.PropertyContext.GetString(PropertyIDRecordKey, 1252); err == nil {
+		t.Fatalf("expected an error for a binary property")
+	}
+}
+
+func TestGetPropertyContext(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	tests := []struct {
+		name                 string
+		nodeIdentifier       int
+		expectedMessageClass string
+		isErrorPresent       bool
+	}{
+		{name: "message", nodeIdentifier: 0x200024, expectedMessageClass: "IPM.Appointment"},
+		// Node 0x1e1 has no data block.
+		{name: "node without data", nodeIdentifier: 0x1e1, isErrorPresent: true},
+		{name: "missing node", nodeIdentifier: 0x1fffe4, isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			propertyContext, err := pst.GetPropertyContext(FormatType32, test.nodeIdentifier)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %d records", len(propertyContext.Records))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			messageClass, err := propertyContext.GetString(PropertyIDMessageClass, 1252)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if messageClass != test.expectedMessageClass {
+				t.Fatalf("expected message class %q, got %q", test.expectedMessageClass, messageClass)
+			}
+		})
+	}
+}
+
+func TestGetPropertyContextOfNodeEntry(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	nodeBTree, err := pst.GetNodeBTree(FormatType32)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	blockBTree, err := pst.GetBlockBTree(FormatType32)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	nodeBTreeEntries, err := pst.GetBTreeLeafNodeEntries(FormatType32, nodeBTree)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	blockBTreeEntries, err := pst.GetBTreeLeafNodeEntries(FormatType32, blockBTree)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	nodeBTreeEntryMap := GetBTreeNodeEntryMap(nodeBTreeEntries)
+	blockBTreeEntryMap := GetBTreeNodeEntryMap(blockBTreeEntries)
+
+	tests := []struct {
+		name                 string
+		nodeIdentifier       int
+		blockBTreeEntries    map[int]BTreeNodeEntry
+		expectedMessageClass string
+		isErrorPresent       bool
+	}{
+		{name: "message", nodeIdentifier: 0x200024, blockBTreeEntries: blockBTreeEntryMap, expectedMessageClass: "IPM.Appointment"},
+		// Node 0x1e1 has no data block.
+		{name: "node without data", nodeIdentifier: 0x1e1, blockBTreeEntries: blockBTreeEntryMap, isErrorPresent: true},
+		{name: "missing data block", nodeIdentifier: 0x200024, blockBTreeEntries: map[int]BTreeNodeEntry{}, isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			propertyContext, err := pst.GetPropertyContextOfNodeEntry(FormatType32, EncryptionTypePermute, nodeBTreeEntryMap[test.nodeIdentifier], test.blockBTreeEntries)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %d records", len(propertyContext.Records))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			messageClass, err := propertyContext.GetString(PropertyIDMessageClass, 1252)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if messageClass != test.expectedMessageClass {
+				t.Fatalf("expected message class %q, got %q", test.expectedMessageClass, messageClass)
+			}
+		})
+	}
+}
@@ -157,4 +157,67 @@ func (pff *PFF) GetEncryptionType(formatType string) (string, error) {
 	} else {
 		return "", errors.New("unsupported encryption type")
 	}
-}
\ No newline at end of file
+}
+
+// GetFileSize returns the file size stored in the header (ibFileEof).
+//
+// References "2.3. The 32-bit header data" and "2.4. The 64-bit header data".
+func (pff *PFF) GetFileSize(formatType string) (int, error) {
+	if formatType == FormatType64 || formatType == FormatType64With4k {
+		fileSize, err := pff.Read(8, 184)
+
+		if err != nil {
+			return -1, err
+		}
+
+		return int(binary.LittleEndian.Uint64(fileSize)), nil
+	} else if formatType == FormatType32 {
+		fileSize, err := pff.Read(4, 168)
+
+		if err != nil {
+			return -1, err
+		}
+
+		return int(binary.LittleEndian.Uint32(fileSize)), nil
+	} else {
+		return -1, errors.New("unsupported format type")
+	}
+}
+
+// Constants for identifying the allocation map (AMap) status.
+const (
+	AllocationMapStatusInvalid = "invalid"
+	AllocationMapStatusValid1  = "valid"
+	AllocationMapStatusValid2  = "valid-2"
+)
+
+// GetAllocationMapStatus returns the allocation map status.
+//
+// References "2.3. The 32-bit header data" and "2.4. The 64-bit header data":
+// An invalid allocation map must be rebuilt before the file can be written to.
+func (pff *PFF) GetAllocationMapStatus(formatType string) (string, error) {
+	var allocationMapStatus []byte
+	var err error
+
+	if formatType == FormatType64 || formatType == FormatType64With4k {
+		allocationMapStatus, err = pff.Read(1, 248)
+	} else if formatType == FormatType32 {
+		allocationMapStatus, err = pff.Read(1, 200)
+	} else {
+		return "", errors.New("unsupported format type")
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if bytes.Equal(allocationMapStatus, []byte{0}) {
+		return AllocationMapStatusInvalid, nil
+	} else if bytes.Equal(allocationMapStatus, []byte{1}) {
+		return AllocationMapStatusValid1, nil
+	} else if bytes.Equal(allocationMapStatus, []byte{2}) {
+		return AllocationMapStatusValid2, nil
+	} else {
+		return "", errors.New("unsupported allocation map status")
+	}
+}
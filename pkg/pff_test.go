@@ -0,0 +1,80 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"testing"
+)
+
+func TestGetFileSize(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	tests := []struct {
+		name           string
+		formatType     string
+		expected       int
+		isErrorPresent bool
+	}{
+		{name: "32-bit", formatType: FormatType32, expected: 65536},
+		{name: "unsupported format type", formatType: "unknown", isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileSize, err := pst.GetFileSize(test.formatType)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %d", fileSize)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if fileSize != test.expected {
+				t.Fatalf("expected %d, got %d", test.expected, fileSize)
+			}
+		})
+	}
+}
+
+func TestGetAllocationMapStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		pst            PFF
+		formatType     string
+		expected       string
+		isErrorPresent bool
+	}{
+		{name: "valid", pst: New("../data/32-bit.pst"), formatType: FormatType32, expected: AllocationMapStatusValid1},
+		{name: "invalid", pst: getTestCorruptFile(t, 200, 0), formatType: FormatType32, expected: AllocationMapStatusInvalid},
+		{name: "unsupported status", pst: getTestCorruptFile(t, 200, 5), formatType: FormatType32, isErrorPresent: true},
+		{name: "unsupported format type", pst: New("../data/32-bit.pst"), formatType: "unknown", isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			allocationMapStatus, err := test.pst.GetAllocationMapStatus(test.formatType)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", allocationMapStatus)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if allocationMapStatus != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, allocationMapStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"errors"
+	"time"
+)
+
+// Constants for identifying the recurrence frequency.
+//
+// References "[MS-OXOCAL] RecurrencePattern Structure".
+const (
+	RecurrenceFrequencyDaily   = 0x200a
+	RecurrenceFrequencyWeekly  = 0x200b
+	RecurrenceFrequencyMonthly = 0x200c
+	RecurrenceFrequencyYearly  = 0x200d
+)
+
+// Constants for identifying the recurrence pattern type.
+//
+// References "[MS-OXOCAL] RecurrencePattern Structure".
+const (
+	RecurrencePatternTypeDay           = 0x0000
+	RecurrencePatternTypeWeek          = 0x0001
+	RecurrencePatternTypeMonth         = 0x0002
+	RecurrencePatternTypeMonthNth      = 0x0003
+	RecurrencePatternTypeMonthEnd      = 0x0004
+	RecurrencePatternTypeHijriMonth    = 0x000a
+	RecurrencePatternTypeHijriMonthNth = 0x000b
+	RecurrencePatternTypeHijriMonthEnd = 0x000c
+)
+
+// Constants for identifying how the recurrence ends.
+//
+// References "[MS-OXOCAL] RecurrencePattern Structure".
+const (
+	RecurrenceEndTypeAfterDate            = 0x2021
+	RecurrenceEndTypeAfterOccurrenceCount = 0x2022
+	RecurrenceEndTypeNeverEnd             = 0x2023
+	RecurrenceEndTypeNeverEndAlternative  = 0xffffffff
+)
+
+// Constants for identifying which fields of an exception are overridden.
+//
+// References "[MS-OXOCAL] ExceptionInfo Structure".
+const (
+	RecurrenceOverrideSubject          = 0x0001
+	RecurrenceOverrideMeetingType      = 0x0002
+	RecurrenceOverrideReminderDelta    = 0x0004
+	RecurrenceOverrideReminder         = 0x0008
+	RecurrenceOverrideLocation         = 0x0010
+	RecurrenceOverrideBusyStatus       = 0x0020
+	RecurrenceOverrideAttachment       = 0x0040
+	RecurrenceOverrideSubType          = 0x0080
+	RecurrenceOverrideAppointmentColor = 0x0100
+	RecurrenceOverrideExceptionalBody  = 0x0200
+)
+
+// RecurrencePattern represents the recurrence pattern of appointments (PidLidAppointmentRecur) and tasks (PidLidTaskRecurrence).
+type RecurrencePattern struct {
+	Frequency             int
+	PatternType           int
+	CalendarType          int
+	FirstDateTime         int
+	Period                int
+	SlidingFlag           int
+	DayOfWeekMask         int
+	DayOfMonth            int
+	WeekOfMonth           int
+	EndType               int
+	OccurrenceCount       int
+	FirstDayOfWeek        int
+	DeletedInstanceDates  []time.Time
+	ModifiedInstanceDates []time.Time
+	StartDate             time.Time
+	EndDate               time.Time
+}
+
+// RecurrenceException represents a modified instance of a recurring appointment.
+type RecurrenceException struct {
+	StartDateTime     time.Time
+	EndDateTime       time.Time
+	OriginalStartDate time.Time
+	OverrideFlags     int
+	Subject           string
+	MeetingType       int
+	ReminderDelta     int
+	IsReminderSet     bool
+	Location          string
+	BusyStatus        int
+	HasAttachment     bool
+	IsAllDay          bool
+	AppointmentColor  int
+	ChangeHighlight   int
+}
+
+// AppointmentRecurrencePattern represents the recurrence of an appointment including its exceptions.
+type AppointmentRecurrencePattern struct {
+	RecurrencePattern RecurrencePattern
+	ReaderVersion     int
+	WriterVersion     int
+	StartTimeOffset   int
+	EndTimeOffset     int
+	Exceptions        []RecurrenceException
+}
+
+// GetRecurrenceTime returns the time of the amount of minutes since January 1, 1601 (UTC).
+// Recurrence blobs store dates and times in minutes instead of FILETIME values.
+func GetRecurrenceTime(minutes int) time.Time {
+	return time.Unix(int64(minutes)*60-11644473600, 0).UTC()
+}
+
+// GetRecurrencePattern returns the recurrence pattern of a PidLidAppointmentRecur or PidLidTaskRecurrence value.
+//
+// References "[MS-OXOCAL] RecurrencePattern Structure".
+func GetRecurrencePattern(data []byte) (RecurrencePattern, error) {
+	reader := newBlobReader(data)
+
+	recurrencePattern := readRecurrencePattern(reader)
+
+	if reader.Err != nil {
+		return RecurrencePattern{}, reader.Err
+	}
+
+	return recurrencePattern, nil
+}
+
+// readRecurrencePattern reads the recurrence pattern.
+func readRecurrencePattern(reader *blobReader) RecurrencePattern {
+	var recurrencePattern RecurrencePattern
+
+	readerVersion := reader.readUint16()
+	writerVersion := reader.readUint16()
+
+	if reader.Err == nil && (readerVersion != 0x3004 || writerVersion != 0x3004) {
+		reader.Err = errors.New("invalid recurrence pattern version")
+	}
+
+	recurrencePattern.Frequency = reader.readUint16()
+	recurrencePattern.PatternType = reader.readUint16()
+	recurrencePattern.CalendarType = reader.readUint16()
+	recurrencePattern.FirstDateTime = reader.readUint32()
+	recurrencePattern.Period = reader.readUint32()
+	recurrencePattern.SlidingFlag = reader.readUint32()
+
+	// The size of the pattern type specific field depends on the pattern type.
+	switch recurrencePattern.PatternType {
+	case RecurrencePatternTypeDay:
+	case RecurrencePatternTypeWeek:
+		recurrencePattern.DayOfWeekMask = reader.readUint32()
+	case RecurrencePatternTypeMonth, RecurrencePatternTypeMonthEnd, RecurrencePatternTypeHijriMonth, RecurrencePatternTypeHijriMonthEnd:
+		recurrencePattern.DayOfMonth = reader.readUint32()
+	case RecurrencePatternTypeMonthNth, RecurrencePatternTypeHijriMonthNth:
+		recurrencePattern.DayOfWeekMask = reader.readUint32()
+		recurrencePattern.WeekOfMonth = reader.readUint32()
+	default:
+		if reader.Err == nil {
+			reader.Err = errors.New("unsupported recurrence pattern type")
+		}
+	}
+
+	recurrencePattern.EndType = reader.readUint32()
+	recurrencePattern.OccurrenceCount = reader.readUint32()
+	recurrencePattern.FirstDayOfWeek = reader.readUint32()
+
+	deletedInstanceCount := reader.readUint32()
+
+	for i := 0; i < deletedInstanceCount && reader.Err == nil; i++ {
+		recurrencePattern.DeletedInstanceDates = append(recurrencePattern.DeletedInstanceDates, GetRecurrenceTime(reader.readUint32()))
+	}
+
+	modifiedInstanceCount := reader.readUint32()
+
+	for i := 0; i < modifiedInstanceCount && reader.Err == nil; i++ {
+		recurrencePattern.ModifiedInstanceDates = append(recurrencePattern.ModifiedInstanceDates, GetRecurrenceTime(reader.readUint32()))
+	}
+
+	recurrencePattern.StartDate = GetRecurrenceTime(reader.readUint32())
+	recurrencePattern.EndDate = GetRecurrenceTime(reader.readUint32())
+
+	return recurrencePattern
+}
+
+// GetAppointmentRecurrencePattern returns the appointment recurrence pattern of a PidLidAppointmentRecur value.
+//
+// References "[MS-OXOCAL] AppointmentRecurrencePattern Structure", "[MS-OXOCAL] ExceptionInfo Structure" and "[MS-OXOCAL] ExtendedException Structure":
+// The exception info contains the ANSI subject and location, the extended exception
+// contains the Unicode subject and location which are preferred.
+// The code page (see StringDecoder.GetCodePage) is used for the ANSI strings, falling back to Windows-1252 if unsupported.
+func GetAppointmentRecurrencePattern(data []byte, codePage int) (AppointmentRecurrencePattern, error) {
+	reader := newBlobReader(data)
+
+	var appointmentRecurrencePattern AppointmentRecurrencePattern
+
+	appointmentRecurrencePattern.RecurrencePattern = readRecurrencePattern(reader)
+	appointmentRecurrencePattern.ReaderVersion = reader.readUint32()
+	appointmentRecurrencePattern.WriterVersion = reader.readUint32()
+	appointmentRecurrencePattern.StartTimeOffset = reader.readUint32()
+	appointmentRecurrencePattern.EndTimeOffset = reader.readUint32()
+
+	if reader.Err == nil && appointmentRecurrencePattern.ReaderVersion != 0x3006 {
+		return AppointmentRecurrencePattern{}, errors.New("invalid appointment recurrence pattern version")
+	}
+
+	exceptionCount := reader.readUint16()
+	stringDecoder := NewStringDecoder(1252)
+
+	for i := 0; i < exceptionCount && reader.Err == nil; i++ {
+		var exception RecurrenceException
+
+		exception.StartDateTime = GetRecurrenceTime(reader.readUint32())
+		exception.EndDateTime = GetRecurrenceTime(reader.readUint32())
+		exception.OriginalStartDate = GetRecurrenceTime(reader.readUint32())
+		exception.OverrideFlags = reader.readUint16()
+
+		if exception.OverrideFlags&RecurrenceOverrideSubject != 0 {
+			_ = reader.readUint16() // Subject length including the length field
+			exception.Subject, _ = stringDecoder.DecodeString8(reader.read(reader.readUint16()), codePage)
+		}
+
+		if exception.OverrideFlags&RecurrenceOverrideMeetingType != 0 {
+			exception.MeetingType = reader.readUint32()
+		}
+
+		if exception.OverrideFlags&RecurrenceOverrideReminderDelta != 0 {
+			exception.ReminderDelta = reader.readUint32()
+		}
+
+		if exception.OverrideFlags&RecurrenceOverrideReminder != 0 {
+			exception.IsReminderSet = reader.readUint32() != 0
+		}
+
+		if exception.OverrideFlags&RecurrenceOverrideLocation != 0 {
+			_ = reader.readUint16() // Location length including the length field
+			exception.Location, _ = stringDecoder.DecodeString8(reader.read(reader.readUint16()), codePage)
+		}
+
+		if exception.OverrideFlags&RecurrenceOverrideBusyStatus != 0 {
+			exception.BusyStatus = reader.readUint32()
+		}
+
+		if exception.OverrideFlags&RecurrenceOverrideAttachment != 0 {
+			exception.HasAttachment = reader.readUint32() != 0
+		}
+
+		if exception.OverrideFlags&RecurrenceOverrideSubType != 0 {
+			exception.IsAllDay = reader.readUint32() != 0
+		}
+
+		if exception.OverrideFlags&RecurrenceOverrideAppointmentColor != 0 {
+			exception.AppointmentColor = reader.readUint32()
+		}
+
+		appointmentRecurrencePattern.Exceptions = append(appointmentRecurrencePattern.Exceptions, exception)
+	}
+
+	// Reserved block 1
+	reader.read(reader.readUint32())
+
+	for i := 0; i < len(appointmentRecurrencePattern.Exceptions) && reader.Err == nil; i++ {
+		exception := &appointmentRecurrencePattern.Exceptions[i]
+
+		if appointmentRecurrencePattern.WriterVersion >= 0x3009 {
+			changeHighlightSize := reader.readUint32()
+
+			if changeHighlightSize >= 4 {
+				exception.ChangeHighlight = reader.readUint32()
+				reader.read(changeHighlightSize - 4)
+			} else {
+				reader.read(changeHighlightSize)
+			}
+		}
+
+		// Reserved block EE1
+		reader.read(reader.readUint32())
+
+		if exception.OverrideFlags&(RecurrenceOverrideSubject|RecurrenceOverrideLocation) != 0 {
+			// Start, end and original start date repeated from the exception info.
+			reader.read(12)
+
+			if exception.OverrideFlags&RecurrenceOverrideSubject != 0 {
+				exception.Subject = reader.readUTF16(reader.readUint16())
+			}
+
+			if exception.OverrideFlags&RecurrenceOverrideLocation != 0 {
+				exception.Location = reader.readUTF16(reader.readUint16())
+			}
+
+			// Reserved block EE2
+			reader.read(reader.readUint32())
+		}
+	}
+
+	if reader.Err != nil {
+		return AppointmentRecurrencePattern{}, reader.Err
+	}
+
+	return appointmentRecurrencePattern, nil
+}
@@ -0,0 +1,216 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// appendUint16 appends the little-endian 16-bit value to the blob.
+func appendUint16(data []byte, value int) []byte {
+	return append(data, byte(value), byte(value>>8))
+}
+
+// appendUint32 appends the little-endian 32-bit value to the blob.
+func appendUint32(data []byte, value int) []byte {
+	return append(data, byte(value), byte(value>>8), byte(value>>16), byte(value>>24))
+}
+
+// appendUTF16 appends the UTF-16 (little-endian) characters of the text to the blob.
+func appendUTF16(data []byte, text string) []byte {
+	for _, character := range utf16.Encode([]rune(text)) {
+		data = appendUint16(data, int(character))
+	}
+
+	return data
+}
+
+// Minutes since January 1, 1601 of the dates used by the recurrence blobs.
+const (
+	testRecurrenceMarch1  = 0x0d2bf220 // 2021-03-01
+	testRecurrenceMarch8  = 0x0d2c1980 // 2021-03-08
+	testRecurrenceMarch15 = 0x0d2c40e0 // 2021-03-15
+	testRecurrenceMarch16 = 0x0d2c4680 // 2021-03-16
+	testRecurrenceMarch29 = 0x0d2c8fa0 // 2021-03-29
+)
+
+// getTestRecurrencePattern returns a weekly (Monday) recurrence pattern ending after 5 occurrences,
+// with the occurrence of March 8 deleted and the occurrence of March 15 moved to March 16.
+//
+// References "[MS-OXOCAL] RecurrencePattern Structure".
+func getTestRecurrencePattern() []byte {
+	var data []byte
+
+	data = appendUint16(data, 0x3004)                    // Reader version
+	data = appendUint16(data, 0x3004)                    // Writer version
+	data = appendUint16(data, RecurrenceFrequencyWeekly) // Frequency
+	data = appendUint16(data, RecurrencePatternTypeWeek) // Pattern type
+	data = appendUint16(data, 0)                         // Calendar type
+	data = appendUint32(data, 0x1680)                    // First date time
+	data = appendUint32(data, 1)                         // Period
+	data = appendUint32(data, 0)                         // Sliding flag
+	data = appendUint32(data, 0x02)                      // Day of week mask (Monday)
+	data = appendUint32(data, RecurrenceEndTypeAfterOccurrenceCount)
+	data = appendUint32(data, 5) // Occurrence count
+	data = appendUint32(data, 1) // First day of week (Monday)
+	data = appendUint32(data, 2) // Deleted instance count
+	data = appendUint32(data, testRecurrenceMarch8)
+	data = appendUint32(data, testRecurrenceMarch15)
+	data = appendUint32(data, 1) // Modified instance count
+	data = appendUint32(data, testRecurrenceMarch16)
+	data = appendUint32(data, testRecurrenceMarch1)  // Start date
+	data = appendUint32(data, testRecurrenceMarch29) // End date
+
+	return data
+}
+
+// getTestAppointmentRecurrencePattern returns the appointment recurrence pattern (09:00 to 10:00) of the test recurrence pattern,
+// the exception is moved to 10:00 on March 16 with a different subject and location.
+//
+// References "[MS-OXOCAL] AppointmentRecurrencePattern Structure".
+func getTestAppointmentRecurrencePattern() []byte {
+	data := getTestRecurrencePattern()
+
+	data = appendUint32(data, 0x3006) // Reader version
+	data = appendUint32(data, 0x3008) // Writer version
+	data = appendUint32(data, 540)    // Start time offset
+	data = appendUint32(data, 600)    // End time offset
+	data = appendUint16(data, 1)      // Exception count
+
+	// Exception info
+	data = appendUint32(data, testRecurrenceMarch16+600)
+	data = appendUint32(data, testRecurrenceMarch16+660)
+	data = appendUint32(data, testRecurrenceMarch15+540)
+	data = appendUint16(data, RecurrenceOverrideSubject|RecurrenceOverrideLocation)
+	data = appendUint16(data, 8) // Subject length
+	data = appendUint16(data, 7)
+	data = append(data, 0xc2, 0xf1, 0xf2, 0xf0, 0xe5, 0xf7, 0xe0) // "Встреча" (Windows-1251)
+	data = appendUint16(data, 4)                                  // Location length
+	data = appendUint16(data, 3)
+	data = append(data, 0xc7, 0xe0, 0xeb) // "Зал" (Windows-1251)
+
+	data = appendUint32(data, 0) // Reserved block 1
+
+	// Extended exception
+	data = appendUint32(data, 0) // Reserved block EE1
+	data = appendUint32(data, testRecurrenceMarch16+600)
+	data = appendUint32(data, testRecurrenceMarch16+660)
+	data = appendUint32(data, testRecurrenceMarch15+540)
+	data = appendUint16(data, 7)
+	data = appendUTF16(data, "Встреча")
+	data = appendUint16(data, 3)
+	data = appendUTF16(data, "Зал")
+	data = appendUint32(data, 0) // Reserved block EE2
+
+	data = appendUint32(data, 0) // Reserved block 2
+
+	return data
+}
+
+func TestGetRecurrenceTime(t *testing.T) {
+	expected := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if recurrenceTime := GetRecurrenceTime(testRecurrenceMarch1); !recurrenceTime.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, recurrenceTime)
+	}
+}
+
+func TestGetRecurrencePattern(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           []byte
+		isErrorPresent bool
+	}{
+		{name: "weekly", data: getTestRecurrencePattern()},
+		{name: "truncated", data: getTestRecurrencePattern()[:40], isErrorPresent: true},
+		{name: "invalid version", data: append([]byte{0x04, 0x31}, getTestRecurrencePattern()[2:]...), isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recurrencePattern, err := GetRecurrencePattern(test.data)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", recurrencePattern)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if recurrencePattern.Frequency != RecurrenceFrequencyWeekly || recurrencePattern.PatternType != RecurrencePatternTypeWeek {
+				t.Fatalf("unexpected frequency 0x%x or pattern type 0x%x", recurrencePattern.Frequency, recurrencePattern.PatternType)
+			}
+
+			if recurrencePattern.DayOfWeekMask != 0x02 || recurrencePattern.OccurrenceCount != 5 || recurrencePattern.FirstDayOfWeek != 1 {
+				t.Fatalf("unexpected pattern %+v", recurrencePattern)
+			}
+
+			if len(recurrencePattern.DeletedInstanceDates) != 2 || !recurrencePattern.DeletedInstanceDates[1].Equal(time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC)) {
+				t.Fatalf("unexpected deleted instance dates %v", recurrencePattern.DeletedInstanceDates)
+			}
+
+			if len(recurrencePattern.ModifiedInstanceDates) != 1 || !recurrencePattern.ModifiedInstanceDates[0].Equal(time.Date(2021, 3, 16, 0, 0, 0, 0, time.UTC)) {
+				t.Fatalf("unexpected modified instance dates %v", recurrencePattern.ModifiedInstanceDates)
+			}
+
+			if !recurrencePattern.EndDate.Equal(time.Date(2021, 3, 29, 0, 0, 0, 0, time.UTC)) {
+				t.Fatalf("unexpected end date %s", recurrencePattern.EndDate)
+			}
+		})
+	}
+}
+
+func TestGetAppointmentRecurrencePattern(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           []byte
+		isErrorPresent bool
+	}{
+		{name: "exception", data: getTestAppointmentRecurrencePattern()},
+		{name: "truncated exception", data: getTestAppointmentRecurrencePattern()[:100], isErrorPresent: true},
+		{name: "truncated extended exception", data: getTestAppointmentRecurrencePattern()[:150], isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			appointmentRecurrencePattern, err := GetAppointmentRecurrencePattern(test.data, 1251)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", appointmentRecurrencePattern)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if appointmentRecurrencePattern.StartTimeOffset != 540 || appointmentRecurrencePattern.EndTimeOffset != 600 {
+				t.Fatalf("unexpected time offsets %d and %d", appointmentRecurrencePattern.StartTimeOffset, appointmentRecurrencePattern.EndTimeOffset)
+			}
+
+			if len(appointmentRecurrencePattern.Exceptions) != 1 {
+				t.Fatalf("expected 1 exception, got %d", len(appointmentRecurrencePattern.Exceptions))
+			}
+
+			exception := appointmentRecurrencePattern.Exceptions[0]
+
+			if !exception.StartDateTime.Equal(time.Date(2021, 3, 16, 10, 0, 0, 0, time.UTC)) || !exception.OriginalStartDate.Equal(time.Date(2021, 3, 15, 9, 0, 0, 0, time.UTC)) {
+				t.Fatalf("unexpected exception dates %s and %s", exception.StartDateTime, exception.OriginalStartDate)
+			}
+
+			if exception.Subject != "Встреча" || exception.Location != "Зал" {
+				t.Fatalf("unexpected subject %q or location %q", exception.Subject, exception.Location)
+			}
+		})
+	}
+}
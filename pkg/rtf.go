@@ -0,0 +1,461 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Constants for identifying the content encapsulated in an RTF body.
+//
+// References "[MS-OXRTFEX] FROMTEXT and FROMHTML control words".
+const (
+	RTFEncapsulationTypeNone = "none"
+	RTFEncapsulationTypeHTML = "html"
+	RTFEncapsulationTypeText = "text"
+)
+
+// GetRTFEncapsulationType returns the encapsulation type of the RTF body.
+//
+// References "[MS-OXRTFEX] Recognizing RTF containing encapsulation":
+// The \fromhtml1 or \fromtext control word must appear in the RTF header, before any document text.
+func GetRTFEncapsulationType(rtf []byte) (string, error) {
+	if !bytes.HasPrefix(rtf, []byte("{\\rtf1")) {
+		return "", errors.New("invalid RTF signature")
+	}
+
+	// Only the first 10 control words are allowed to contain the encapsulation control word.
+	for i, controlWords := 1, 0; i < len(rtf) && controlWords < 10; i++ {
+		if rtf[i] != '\\' {
+			continue
+		}
+
+		word, parameter, hasParameter, _ := readRTFControlWord(rtf, i+1)
+
+		if word == "" {
+			continue
+		}
+
+		controlWords++
+
+		if word == "fromhtml" && hasParameter && parameter == 1 {
+			return RTFEncapsulationTypeHTML, nil
+		} else if word == "fromtext" {
+			return RTFEncapsulationTypeText, nil
+		}
+	}
+
+	return RTFEncapsulationTypeNone, nil
+}
+
+// DeEncapsulateRTF returns the original HTML or plain text encapsulated in the RTF body.
+//
+// References "[MS-OXRTFEX] De-encapsulating HTML and plain text from RTF":
+// Content inside \*\htmltag destinations is always part of the original HTML.
+// Any other text is part of the original content unless it is suppressed by \htmlrtf.
+func DeEncapsulateRTF(rtf []byte) (string, error) {
+	encapsulationType, err := GetRTFEncapsulationType(rtf)
+
+	if err != nil {
+		return "", err
+	}
+
+	if encapsulationType == RTFEncapsulationTypeNone {
+		return "", errors.New("RTF does not contain encapsulated content")
+	}
+
+	deEncapsulator := newRTFDeEncapsulator(encapsulationType)
+
+	if err := deEncapsulator.parse(rtf); err != nil {
+		return "", err
+	}
+
+	return deEncapsulator.Output.String(), nil
+}
+
+// Constants for identifying the destination of an RTF group.
+const (
+	rtfDestinationNormal = iota
+	rtfDestinationSkip
+	rtfDestinationFontTable
+	rtfDestinationHTMLTag
+)
+
+// rtfSkippedDestinations contains destinations which never contain encapsulated content.
+var rtfSkippedDestinations = map[string]bool{
+	"colortbl":          true,
+	"stylesheet":        true,
+	"info":              true,
+	"pict":              true,
+	"object":            true,
+	"listtable":         true,
+	"listoverridetable": true,
+	"rsidtbl":           true,
+	"generator":         true,
+	"filetbl":           true,
+	"revtbl":            true,
+	"header":            true,
+	"footer":            true,
+	"xmlnstbl":          true,
+	"themedata":         true,
+	"datastore":         true,
+	"latentstyles":      true,
+	"mhtmltag":          true,
+}
+
+// rtfControlWordText contains control words which map to text.
+var rtfControlWordText = map[string]string{
+	"par":       "\r\n",
+	"line":      "\r\n",
+	"tab":       "\t",
+	"lquote":    "‘",
+	"rquote":    "’",
+	"ldblquote": "“",
+	"rdblquote": "”",
+	"bullet":    "•",
+	"endash":    "–",
+	"emdash":    "—",
+	"enspace":   " ",
+	"emspace":   " ",
+}
+
+// rtfCharsetCodePages maps RTF font character sets (\fcharset) to Windows code pages.
+var rtfCharsetCodePages = map[int]int{
+	0:   1252,
+	128: 932,
+	129: 949,
+	134: 936,
+	136: 950,
+	161: 1253,
+	162: 1254,
+	163: 1258,
+	177: 1255,
+	178: 1256,
+	186: 1257,
+	204: 1251,
+	222: 874,
+	238: 1250,
+}
+
+// rtfGroupState represents the state of an RTF group, which is restored when the group ends.
+type rtfGroupState struct {
+	Destination       int
+	IsSuppressed      bool
+	CodePage          int
+	UnicodeSkipCount  int
+	FontNumber        int
+	IsFirstControl    bool
+	IsIgnorableMarked bool
+}
+
+// rtfDeEncapsulator represents the state of de-encapsulating an RTF body.
+type rtfDeEncapsulator struct {
+	EncapsulationType string
+	DefaultCodePage   int
+	FontCodePages     map[int]int
+	State             rtfGroupState
+	States            []rtfGroupState
+	PendingSkipCount  int
+	PendingBytes      []byte
+	PendingCodePage   int
+	Output            strings.Builder
+}
+
+// newRTFDeEncapsulator is a constructor for RTF de-encapsulation.
+func newRTFDeEncapsulator(encapsulationType string) *rtfDeEncapsulator {
+	return &rtfDeEncapsulator{
+		EncapsulationType: encapsulationType,
+		DefaultCodePage:   1252,
+		FontCodePages:     make(map[int]int),
+		State: rtfGroupState{
+			CodePage:         1252,
+			UnicodeSkipCount: 1,
+		},
+	}
+}
+
+// readRTFControlWord reads the control word starting at the given offset (after the backslash).
+// Returns the control word, parameter and the offset after the control word including its delimiting space.
+func readRTFControlWord(rtf []byte, offset int) (string, int, bool, int) {
+	start := offset
+
+	for offset < len(rtf) && ((rtf[offset] >= 'a' && rtf[offset] <= 'z') || (rtf[offset] >= 'A' && rtf[offset] <= 'Z')) {
+		offset++
+	}
+
+	word := string(rtf[start:offset])
+
+	parameterStart := offset
+
+	if offset < len(rtf) && rtf[offset] == '-' {
+		offset++
+	}
+
+	for offset < len(rtf) && rtf[offset] >= '0' && rtf[offset] <= '9' {
+		offset++
+	}
+
+	parameter, err := strconv.Atoi(string(rtf[parameterStart:offset]))
+	hasParameter := err == nil
+
+	if !hasParameter {
+		offset = parameterStart
+	}
+
+	if offset < len(rtf) && rtf[offset] == ' ' {
+		offset++
+	}
+
+	return word, parameter, hasParameter, offset
+}
+
+// isOutputEnabled returns true if the current group contributes to the encapsulated content.
+func (deEncapsulator *rtfDeEncapsulator) isOutputEnabled() bool {
+	if deEncapsulator.State.IsSuppressed {
+		return false
+	}
+
+	return deEncapsulator.State.Destination == rtfDestinationNormal || deEncapsulator.State.Destination == rtfDestinationHTMLTag
+}
+
+// writeByte writes a byte in the code page of the current group.
+func (deEncapsulator *rtfDeEncapsulator) writeByte(character byte) {
+	if deEncapsulator.PendingCodePage != deEncapsulator.State.CodePage {
+		deEncapsulator.flush()
+		deEncapsulator.PendingCodePage = deEncapsulator.State.CodePage
+	}
+
+	deEncapsulator.PendingBytes = append(deEncapsulator.PendingBytes, character)
+}
+
+// writeString writes text which is already decoded.
+func (deEncapsulator *rtfDeEncapsulator) writeString(text string) {
+	deEncapsulator.flush()
+	deEncapsulator.Output.WriteString(text)
+}
+
+// flush decodes the pending code page bytes to the output.
+func (deEncapsulator *rtfDeEncapsulator) flush() {
+	if len(deEncapsulator.PendingBytes) == 0 {
+		return
+	}
+
+	codePageEncoding, err := GetCodePageEncoding(deEncapsulator.PendingCodePage)
+
+	if err != nil {
+		codePageEncoding, _ = GetCodePageEncoding(1252)
+	}
+
+	decoded, err := codePageEncoding.NewDecoder().Bytes(deEncapsulator.PendingBytes)
+
+	if err != nil {
+		decoded = deEncapsulator.PendingBytes
+	}
+
+	deEncapsulator.Output.Write(decoded)
+	deEncapsulator.PendingBytes = deEncapsulator.PendingBytes[:0]
+}
+
+// parse walks the RTF tokens and writes the encapsulated content.
+func (deEncapsulator *rtfDeEncapsulator) parse(rtf []byte) error {
+	var highSurrogate rune
+
+	for i := 0; i < len(rtf); {
+		character := rtf[i]
+
+		switch character {
+		case '{':
+			// Skippable data after \uN ends at a group delimiter.
+			deEncapsulator.PendingSkipCount = 0
+			deEncapsulator.States = append(deEncapsulator.States, deEncapsulator.State)
+			deEncapsulator.State.IsFirstControl = true
+			deEncapsulator.State.IsIgnorableMarked = false
+			i++
+		case '}':
+			if len(deEncapsulator.States) == 0 {
+				// Trailing content after the document group is ignored.
+				deEncapsulator.flush()
+
+				return nil
+			}
+
+			deEncapsulator.PendingSkipCount = 0
+			deEncapsulator.State = deEncapsulator.States[len(deEncapsulator.States)-1]
+			deEncapsulator.States = deEncapsulator.States[:len(deEncapsulator.States)-1]
+			i++
+		case '\r', '\n':
+			i++
+		case '\\':
+			if i+1 >= len(rtf) {
+				return errors.New("truncated RTF control symbol")
+			}
+
+			symbol := rtf[i+1]
+
+			if (symbol >= 'a' && symbol <= 'z') || (symbol >= 'A' && symbol <= 'Z') {
+				word, parameter, hasParameter, offset := readRTFControlWord(rtf, i+1)
+				i = offset
+
+				if word != "u" {
+					highSurrogate = 0
+				}
+
+				deEncapsulator.handleControlWord(word, parameter, hasParameter, &highSurrogate)
+
+				continue
+			}
+
+			deEncapsulator.State.IsFirstControl = false
+			i += 2
+
+			switch symbol {
+			case '*':
+				deEncapsulator.State.IsIgnorableMarked = true
+				deEncapsulator.State.IsFirstControl = true
+			case '\'':
+				if i+2 > len(rtf) {
+					return errors.New("truncated RTF hexadecimal escape")
+				}
+
+				value, err := strconv.ParseUint(string(rtf[i:i+2]), 16, 8)
+				i += 2
+
+				if err != nil {
+					return errors.New("invalid RTF hexadecimal escape")
+				}
+
+				if deEncapsulator.PendingSkipCount > 0 {
+					deEncapsulator.PendingSkipCount--
+				} else if deEncapsulator.isOutputEnabled() {
+					deEncapsulator.writeByte(byte(value))
+				}
+			case '\r', '\n':
+				deEncapsulator.writeControlText("\r\n")
+			case '~':
+				deEncapsulator.writeControlText(" ")
+			case '_':
+				deEncapsulator.writeControlText("‑")
+			case '{', '}', '\\':
+				deEncapsulator.writeControlText(string(symbol))
+			}
+		default:
+			deEncapsulator.State.IsFirstControl = false
+			i++
+
+			if deEncapsulator.PendingSkipCount > 0 {
+				deEncapsulator.PendingSkipCount--
+			} else if deEncapsulator.isOutputEnabled() {
+				deEncapsulator.writeByte(character)
+			}
+		}
+	}
+
+	deEncapsulator.flush()
+
+	return nil
+}
+
+// writeControlText writes the text of a control word or symbol, which counts as one skippable character after \u.
+func (deEncapsulator *rtfDeEncapsulator) writeControlText(text string) {
+	if deEncapsulator.PendingSkipCount > 0 {
+		deEncapsulator.PendingSkipCount--
+	} else if deEncapsulator.isOutputEnabled() {
+		deEncapsulator.writeString(text)
+	}
+}
+
+// handleControlWord updates the state or writes the output for a control word.
+func (deEncapsulator *rtfDeEncapsulator) handleControlWord(word string, parameter int, hasParameter bool, highSurrogate *rune) {
+	isFirstControl := deEncapsulator.State.IsFirstControl
+	deEncapsulator.State.IsFirstControl = false
+
+	if deEncapsulator.PendingSkipCount > 0 {
+		// Every control word counts as one skippable character after \u, not only the ones producing text.
+		deEncapsulator.PendingSkipCount--
+
+		return
+	}
+
+	if isFirstControl {
+		// The first control word of a group may change its destination.
+		if word == "fonttbl" {
+			deEncapsulator.State.Destination = rtfDestinationFontTable
+
+			return
+		} else if word == "htmltag" && deEncapsulator.EncapsulationType == RTFEncapsulationTypeHTML {
+			deEncapsulator.State.Destination = rtfDestinationHTMLTag
+			deEncapsulator.State.IsSuppressed = false
+
+			return
+		} else if rtfSkippedDestinations[word] || deEncapsulator.State.IsIgnorableMarked {
+			deEncapsulator.State.Destination = rtfDestinationSkip
+
+			return
+		}
+	}
+
+	switch word {
+	case "ansicpg":
+		if hasParameter {
+			deEncapsulator.DefaultCodePage = parameter
+			deEncapsulator.State.CodePage = parameter
+		}
+	case "f":
+		if deEncapsulator.State.Destination == rtfDestinationFontTable {
+			deEncapsulator.State.FontNumber = parameter
+		} else if codePage, ok := deEncapsulator.FontCodePages[parameter]; ok {
+			deEncapsulator.State.CodePage = codePage
+		} else {
+			deEncapsulator.State.CodePage = deEncapsulator.DefaultCodePage
+		}
+	case "fcharset":
+		if deEncapsulator.State.Destination == rtfDestinationFontTable {
+			if codePage, ok := rtfCharsetCodePages[parameter]; ok && parameter != 0 {
+				deEncapsulator.FontCodePages[deEncapsulator.State.FontNumber] = codePage
+			} else {
+				deEncapsulator.FontCodePages[deEncapsulator.State.FontNumber] = deEncapsulator.DefaultCodePage
+			}
+		}
+	case "cpg":
+		if deEncapsulator.State.Destination == rtfDestinationFontTable {
+			deEncapsulator.FontCodePages[deEncapsulator.State.FontNumber] = parameter
+		}
+	case "htmlrtf":
+		deEncapsulator.State.IsSuppressed = !hasParameter || parameter != 0
+	case "uc":
+		if hasParameter && parameter >= 0 {
+			deEncapsulator.State.UnicodeSkipCount = parameter
+		}
+	case "u":
+		if !hasParameter {
+			return
+		}
+
+		codePoint := rune(parameter)
+
+		if codePoint < 0 {
+			codePoint += 0x10000
+		}
+
+		if deEncapsulator.isOutputEnabled() {
+			if utf16.IsSurrogate(codePoint) && codePoint < 0xdc00 {
+				*highSurrogate = codePoint
+			} else if *highSurrogate != 0 {
+				deEncapsulator.writeString(string(utf16.DecodeRune(*highSurrogate, codePoint)))
+				*highSurrogate = 0
+			} else {
+				deEncapsulator.writeString(string(codePoint))
+			}
+		}
+
+		deEncapsulator.PendingSkipCount = deEncapsulator.State.UnicodeSkipCount
+	default:
+		if text, ok := rtfControlWordText[word]; ok {
+			deEncapsulator.writeControlText(text)
+		}
+	}
+}
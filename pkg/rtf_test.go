@@ -0,0 +1,132 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"testing"
+)
+
+func TestGetRTFEncapsulationType(t *testing.T) {
+	tests := []struct {
+		name           string
+		rtf            string
+		expected       string
+		isErrorPresent bool
+	}{
+		{name: "HTML", rtf: "{\\rtf1\\ansi\\ansicpg1252\\fromhtml1 \\deff0 text}", expected: RTFEncapsulationTypeHTML},
+		{name: "plain text", rtf: "{\\rtf1\\ansi\\fromtext \\deff0 text}", expected: RTFEncapsulationTypeText},
+		{name: "none", rtf: "{\\rtf1\\ansi\\deff0 text}", expected: RTFEncapsulationTypeNone},
+		{name: "invalid signature", rtf: "{\\rtf", isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encapsulationType, err := GetRTFEncapsulationType([]byte(test.rtf))
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", encapsulationType)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if encapsulationType != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, encapsulationType)
+			}
+		})
+	}
+}
+
+func TestDeEncapsulateRTF(t *testing.T) {
+	tests := []struct {
+		name           string
+		rtf            string
+		expected       string
+		isErrorPresent bool
+	}{
+		{
+			// Based on "[MS-OXRTFEX] Example of encapsulated HTML".
+			name: "HTML",
+			rtf: "{\\rtf1\\ansi\\ansicpg1252\\fromhtml1 \\deff0{\\fonttbl\r\n" +
+				"{\\f0\\fswiss Arial;}\r\n" +
+				"{\\f1\\fmodern Courier New;}}\r\n" +
+				"{\\colortbl\\red0\\green0\\blue0;\\red0\\green0\\blue255;}\r\n" +
+				"\\uc1\\pard\\plain\\deftab360 \\f0\\fs24 \r\n" +
+				"{\\*\\htmltag19 <html>}\r\n" +
+				"{\\*\\htmltag50 <body>}\r\n" +
+				"\\htmlrtf {\\htmlrtf0 \r\n" +
+				"{\\*\\htmltag64 <p>}Hello\\htmlrtf \\par\\htmlrtf0 {\\*\\htmltag72 </p>}\r\n" +
+				"\\htmlrtf }\\htmlrtf0 \r\n" +
+				"{\\*\\htmltag58 </body>}\r\n" +
+				"{\\*\\htmltag27 </html>}}",
+			expected: "<html><body><p>Hello</p></body></html>",
+		},
+		{
+			name:     "plain text",
+			rtf:      "{\\rtf1\\ansi\\ansicpg1252\\fromtext \\deff0{\\fonttbl{\\f0\\fswiss Arial;}}\\pard caf\\'e9\\par \\tab end}",
+			expected: "café\r\n\tend",
+		},
+		{
+			name:     "code page of the font",
+			rtf:      "{\\rtf1\\ansi\\ansicpg1252\\fromtext \\deff0{\\fonttbl{\\f0\\fswiss Arial;}{\\f1\\fcharset204 Arial;}}\\f1 \\'cf\\'f0\\'e8\\f0 \\'e9}",
+			expected: "Приé",
+		},
+		{
+			name:     "unicode with fallback character",
+			rtf:      "{\\rtf1\\ansi\\fromtext \\uc1 \\u8364?5}",
+			expected: "€5",
+		},
+		{
+			name:     "unicode surrogate pair",
+			rtf:      "{\\rtf1\\ansi\\fromtext \\uc1 \\u-10179?\\u-8704?}",
+			expected: "😀",
+		},
+		{
+			// Every control word counts as a skippable fallback character, not only the ones producing text.
+			name:     "unicode followed by a control word",
+			rtf:      "{\\rtf1\\ansi\\fromtext \\uc1 \\u8364\\f1 X}",
+			expected: "€X",
+		},
+		{
+			// Skippable data after \uN ends at a group delimiter.
+			name:     "unicode at the end of a group",
+			rtf:      "{\\rtf1\\ansi\\fromtext \\uc1 {\\u8364}X}",
+			expected: "€X",
+		},
+		{
+			name:     "escaped symbols",
+			rtf:      "{\\rtf1\\ansi\\fromtext \\{a\\}\\\\b\\~c}",
+			expected: "{a}\\b\u00a0c",
+		},
+		{name: "no encapsulation", rtf: "{\\rtf1\\ansi text}", isErrorPresent: true},
+		{name: "truncated control symbol", rtf: "{\\rtf1\\ansi\\fromtext text\\", isErrorPresent: true},
+		{name: "truncated hexadecimal escape", rtf: "{\\rtf1\\ansi\\fromtext text\\'e", isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text, err := DeEncapsulateRTF([]byte(test.rtf))
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", text)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if text != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, text)
+			}
+		})
+	}
+}
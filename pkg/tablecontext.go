@@ -0,0 +1,146 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// TableContextInfo represents the header (TCINFO) of a table context (TC).
+// The row data is ordered by column size: 8 and 4 bytes columns, 2 bytes columns, 1 byte columns and the cell existence bitmap.
+type TableContextInfo struct {
+	ColumnCount         int
+	FourByteColumnsEnd  int
+	TwoByteColumnsEnd   int
+	OneByteColumnsEnd   int
+	RowSize             int
+	RowIndexIdentifier  int
+	RowMatrixIdentifier int
+	Columns             []TableContextColumn
+}
+
+// TableContextColumn represents a column description (TCOLDESC) of a table context.
+type TableContextColumn struct {
+	PropertyID       int
+	PropertyType     int
+	Offset           int
+	Size             int
+	CellExistenceBit int
+}
+
+// GetTableContextInfo returns the table context header stored in the first data block of a node.
+//
+// References "[MS-PST] 2.3.4.1 TCINFO" and "[MS-PST] 2.3.4.2 TCOLDESC".
+func GetTableContextInfo(data []byte) (TableContextInfo, error) {
+	heapOnNodeHeader, err := GetHeapOnNodeHeader(data)
+
+	if err != nil {
+		return TableContextInfo{}, err
+	}
+
+	if heapOnNodeHeader.ClientSignature != HeapOnNodeClientSignatureTable {
+		return TableContextInfo{}, errors.New("heap-on-node is not a table context")
+	}
+
+	tableContextInfo, err := GetHeapOnNodeAllocation(data, heapOnNodeHeader.UserRootIdentifier)
+
+	if err != nil {
+		return TableContextInfo{}, err
+	}
+
+	if len(tableContextInfo) < 22 || tableContextInfo[0] != HeapOnNodeClientSignatureTable {
+		return TableContextInfo{}, errors.New("invalid table context header")
+	}
+
+	columnCount := int(tableContextInfo[1])
+
+	if 22+columnCount*8 > len(tableContextInfo) {
+		return TableContextInfo{}, errors.New("invalid table context column count")
+	}
+
+	info := TableContextInfo{
+		ColumnCount:         columnCount,
+		FourByteColumnsEnd:  int(binary.LittleEndian.Uint16(tableContextInfo[2:4])),
+		TwoByteColumnsEnd:   int(binary.LittleEndian.Uint16(tableContextInfo[4:6])),
+		OneByteColumnsEnd:   int(binary.LittleEndian.Uint16(tableContextInfo[6:8])),
+		RowSize:             int(binary.LittleEndian.Uint16(tableContextInfo[8:10])),
+		RowIndexIdentifier:  int(binary.LittleEndian.Uint32(tableContextInfo[10:14])),
+		RowMatrixIdentifier: int(binary.LittleEndian.Uint32(tableContextInfo[14:18])),
+		Columns:             make([]TableContextColumn, columnCount),
+	}
+
+	// The deprecated index (hidIndex) is not used.
+	if info.FourByteColumnsEnd > info.TwoByteColumnsEnd || info.TwoByteColumnsEnd > info.OneByteColumnsEnd || info.OneByteColumnsEnd > info.RowSize {
+		return TableContextInfo{}, errors.New("invalid table context row size")
+	}
+
+	for i := 0; i < columnCount; i++ {
+		column := tableContextInfo[22+i*8 : 22+(i+1)*8]
+		propertyTag := binary.LittleEndian.Uint32(column[0:4])
+
+		info.Columns[i] = TableContextColumn{
+			PropertyID:       int(propertyTag >> 16),
+			PropertyType:     int(propertyTag & 0xffff),
+			Offset:           int(binary.LittleEndian.Uint16(column[4:6])),
+			Size:             int(column[6]),
+			CellExistenceBit: int(column[7]),
+		}
+
+		if info.Columns[i].Offset+info.Columns[i].Size > info.OneByteColumnsEnd || info.Columns[i].CellExistenceBit/8 >= info.RowSize-info.OneByteColumnsEnd {
+			return TableContextInfo{}, errors.New("invalid table context column")
+		}
+	}
+
+	return info, nil
+}
+
+// GetTableContextRows returns the rows of the row matrix of the table context.
+//
+// References "[MS-PST] 2.3.4.4 Row Matrix":
+// The row matrix is stored in the heap-on-node if it fits, otherwise in a local descriptor (subnode) which is not supported.
+func GetTableContextRows(data []byte, tableContextInfo TableContextInfo) ([][]byte, error) {
+	if tableContextInfo.RowMatrixIdentifier == 0 {
+		// Empty table
+		return nil, nil
+	} else if tableContextInfo.RowMatrixIdentifier&0x1f != 0 {
+		return nil, errors.New("table context rows stored in local descriptors are not supported")
+	}
+
+	if tableContextInfo.RowSize == 0 {
+		return nil, errors.New("invalid table context row size")
+	}
+
+	rowMatrix, err := GetHeapOnNodeAllocation(data, tableContextInfo.RowMatrixIdentifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]byte
+
+	for i := 0; i+tableContextInfo.RowSize <= len(rowMatrix); i += tableContextInfo.RowSize {
+		rows = append(rows, rowMatrix[i:i+tableContextInfo.RowSize])
+	}
+
+	return rows, nil
+}
+
+// GetCell returns the data of the column in the row, or false if the cell does not exist.
+// Variable size values are stored as a heap identifier or local descriptor identifier (HNID).
+//
+// References "[MS-PST] 2.3.4.4.1 Row Data Format":
+// The cell existence bitmap starts after the 1 byte columns, the bits are ordered from the most significant bit.
+func (tableContextInfo *TableContextInfo) GetCell(row []byte, column TableContextColumn) ([]byte, bool) {
+	if len(row) < tableContextInfo.RowSize {
+		return nil, false
+	}
+
+	cellExistence := row[tableContextInfo.OneByteColumnsEnd+column.CellExistenceBit/8]
+
+	if cellExistence&(0x80>>(column.CellExistenceBit%8)) == 0 {
+		return nil, false
+	}
+
+	return row[column.Offset : column.Offset+column.Size], true
+}
@@ -0,0 +1,101 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestGetTableContextRows(t *testing.T) {
+	pst := New("../data/32-bit.pst")
+
+	// Node 0x12d is the hierarchy table of the root folder.
+	hierarchyTable, err := pst.GetNodeData(FormatType32, 0x12d)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	emptyTable, err := pst.GetNodeData(FormatType32, 0x12e)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	propertyContext, err := pst.GetNodeData(FormatType32, NodeBTreeIdentifierMessageStore)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name                   string
+		data                   []byte
+		expectedRowIdentifiers []int
+		isErrorPresent         bool
+	}{
+		{name: "hierarchy table", data: hierarchyTable, expectedRowIdentifiers: []int{0x8022, 0x8062}},
+		{name: "empty table", data: emptyTable},
+		{name: "property context", data: propertyContext, isErrorPresent: true},
+		{name: "truncated", data: hierarchyTable[:100], isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tableContextInfo, err := GetTableContextInfo(test.data)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %d columns", tableContextInfo.ColumnCount)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			rows, err := GetTableContextRows(test.data, tableContextInfo)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(rows) != len(test.expectedRowIdentifiers) {
+				t.Fatalf("expected %d rows, got %d", len(test.expectedRowIdentifiers), len(rows))
+			}
+
+			// Every table context contains the row identifier (PidTagLtpRowId) at the start of the row.
+			var rowIdentifierColumn TableContextColumn
+
+			for _, column := range tableContextInfo.Columns {
+				if column.PropertyID == 0x67f2 {
+					rowIdentifierColumn = column
+				}
+			}
+
+			if rowIdentifierColumn.PropertyID != 0x67f2 || rowIdentifierColumn.Offset != 0 || rowIdentifierColumn.Size != 4 {
+				t.Fatalf("expected row identifier column, got %+v", rowIdentifierColumn)
+			}
+
+			for i, row := range rows {
+				value, ok := tableContextInfo.GetCell(row, rowIdentifierColumn)
+
+				if !ok || int(binary.LittleEndian.Uint32(value)) != test.expectedRowIdentifiers[i] {
+					t.Fatalf("expected row identifier 0x%x, got %x", test.expectedRowIdentifiers[i], value)
+				}
+			}
+		})
+	}
+}
+
+func TestGetTableContextRowsInLocalDescriptors(t *testing.T) {
+	// The row matrix identifier is a local descriptor node identifier (HNID with a node type).
+	tableContextInfo := TableContextInfo{RowSize: 22, RowMatrixIdentifier: 0x671}
+
+	if _, err := GetTableContextRows(nil, tableContextInfo); err == nil {
+		t.Fatalf("expected an error for a row matrix in local descriptors")
+	}
+}
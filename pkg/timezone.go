@@ -0,0 +1,135 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import "errors"
+
+// SystemTime represents a SYSTEMTIME structure.
+// In time zone rules the day field contains the week of the month (1 to 5, where 5 is the last week).
+type SystemTime struct {
+	Year         int
+	Month        int
+	DayOfWeek    int
+	Day          int
+	Hour         int
+	Minute       int
+	Second       int
+	Milliseconds int
+}
+
+// readSystemTime reads a SYSTEMTIME structure.
+func readSystemTime(reader *blobReader) SystemTime {
+	return SystemTime{
+		Year:         reader.readUint16(),
+		Month:        reader.readUint16(),
+		DayOfWeek:    reader.readUint16(),
+		Day:          reader.readUint16(),
+		Hour:         reader.readUint16(),
+		Minute:       reader.readUint16(),
+		Second:       reader.readUint16(),
+		Milliseconds: reader.readUint16(),
+	}
+}
+
+// TimeZoneRule represents the offsets from UTC and transition dates of a time zone.
+// Biases are in minutes, the local time is UTC minus the bias.
+type TimeZoneRule struct {
+	Year         int
+	Flags        int
+	Bias         int
+	StandardBias int
+	DaylightBias int
+	StandardDate SystemTime
+	DaylightDate SystemTime
+}
+
+// Constants for identifying time zone rule flags.
+//
+// References "[MS-OXOCAL] TZRule Structure".
+const (
+	TimeZoneRuleFlagRecurCurrent = 0x0001
+	TimeZoneRuleFlagEffective    = 0x0002
+)
+
+// TimeZoneDefinition represents a time zone with its rules (one per year in which the rules changed).
+type TimeZoneDefinition struct {
+	KeyName string
+	Rules   []TimeZoneRule
+}
+
+// GetTimeZoneStruct returns the time zone rule of a PidLidTimeZoneStruct value.
+//
+// References "[MS-OXOCAL] PidLidTimeZoneStruct".
+func GetTimeZoneStruct(data []byte) (TimeZoneRule, error) {
+	reader := newBlobReader(data)
+
+	var timeZoneRule TimeZoneRule
+
+	timeZoneRule.Bias = reader.readInt32()
+	timeZoneRule.StandardBias = reader.readInt32()
+	timeZoneRule.DaylightBias = reader.readInt32()
+	timeZoneRule.Year = reader.readUint16()
+	timeZoneRule.StandardDate = readSystemTime(reader)
+	_ = reader.readUint16() // Daylight year, same as the standard year
+	timeZoneRule.DaylightDate = readSystemTime(reader)
+	timeZoneRule.Flags = TimeZoneRuleFlagEffective
+
+	if reader.Err != nil {
+		return TimeZoneRule{}, reader.Err
+	}
+
+	return timeZoneRule, nil
+}
+
+// GetTimeZoneDefinition returns the time zone definition of a PidLidAppointmentTimeZoneDefinitionStartDisplay,
+// PidLidAppointmentTimeZoneDefinitionEndDisplay or PidLidAppointmentTimeZoneDefinitionRecur value.
+//
+// References "[MS-OXOCAL] TimeZoneDefinition Structure" and "[MS-OXOCAL] TZRule Structure".
+func GetTimeZoneDefinition(data []byte) (TimeZoneDefinition, error) {
+	reader := newBlobReader(data)
+
+	majorVersion := reader.readUint8()
+	_ = reader.readUint8() // Minor version
+
+	if reader.Err == nil && majorVersion != 0x02 {
+		return TimeZoneDefinition{}, errors.New("invalid time zone definition version")
+	}
+
+	headerSize := reader.readUint16()
+	headerStart := reader.Offset
+
+	var timeZoneDefinition TimeZoneDefinition
+
+	_ = reader.readUint16() // Reserved
+	timeZoneDefinition.KeyName = reader.readUTF16(reader.readUint16())
+	ruleCount := reader.readUint16()
+
+	if reader.Err == nil {
+		// The rules start after the header.
+		reader.Offset = headerStart + headerSize
+	}
+
+	for i := 0; i < ruleCount && reader.Err == nil; i++ {
+		var timeZoneRule TimeZoneRule
+
+		_ = reader.readUint8()  // Major version
+		_ = reader.readUint8()  // Minor version
+		_ = reader.readUint16() // Reserved
+		timeZoneRule.Flags = reader.readUint16()
+		timeZoneRule.Year = reader.readUint16()
+		reader.read(14) // Reserved
+		timeZoneRule.Bias = reader.readInt32()
+		timeZoneRule.StandardBias = reader.readInt32()
+		timeZoneRule.DaylightBias = reader.readInt32()
+		timeZoneRule.StandardDate = readSystemTime(reader)
+		timeZoneRule.DaylightDate = readSystemTime(reader)
+
+		timeZoneDefinition.Rules = append(timeZoneDefinition.Rules, timeZoneRule)
+	}
+
+	if reader.Err != nil {
+		return TimeZoneDefinition{}, reader.Err
+	}
+
+	return timeZoneDefinition, nil
+}
@@ -0,0 +1,152 @@
+// This file is part of go-pff (https://github.com/mooijtech/go-pff)
+// Copyright (C) 2021 Marten Mooij (https://www.mooijtech.com/)
+package pff
+
+import (
+	"testing"
+)
+
+// appendSystemTime appends the SYSTEMTIME structure to the blob.
+func appendSystemTime(data []byte, systemTime SystemTime) []byte {
+	data = appendUint16(data, systemTime.Year)
+	data = appendUint16(data, systemTime.Month)
+	data = appendUint16(data, systemTime.DayOfWeek)
+	data = appendUint16(data, systemTime.Day)
+	data = appendUint16(data, systemTime.Hour)
+	data = appendUint16(data, systemTime.Minute)
+	data = appendUint16(data, systemTime.Second)
+
+	return appendUint16(data, systemTime.Milliseconds)
+}
+
+// Transition dates of the W. Europe Standard Time time zone (last Sunday of October at 03:00 and last Sunday of March at 02:00).
+var (
+	testStandardDate = SystemTime{Month: 10, DayOfWeek: 0, Day: 5, Hour: 3}
+	testDaylightDate = SystemTime{Month: 3, DayOfWeek: 0, Day: 5, Hour: 2}
+)
+
+// getTestTimeZoneStruct returns the PidLidTimeZoneStruct of the W. Europe Standard Time time zone.
+//
+// References "[MS-OXOCAL] PidLidTimeZoneStruct".
+func getTestTimeZoneStruct() []byte {
+	var data []byte
+
+	data = appendUint32(data, -60) // Bias
+	data = appendUint32(data, 0)   // Standard bias
+	data = appendUint32(data, -60) // Daylight bias
+	data = appendUint16(data, 0)   // Standard year
+	data = appendSystemTime(data, testStandardDate)
+	data = appendUint16(data, 0) // Daylight year
+
+	return appendSystemTime(data, testDaylightDate)
+}
+
+// getTestTimeZoneDefinition returns the TimeZoneDefinition of the W. Europe Standard Time time zone with one rule.
+//
+// References "[MS-OXOCAL] TimeZoneDefinition Structure" and "[MS-OXOCAL] TZRule Structure".
+func getTestTimeZoneDefinition() []byte {
+	keyName := "W. Europe Standard Time"
+
+	var data []byte
+
+	data = append(data, 0x02, 0x01)             // Major and minor version
+	data = appendUint16(data, 6+len(keyName)*2) // Header size
+	data = appendUint16(data, 0x0002)           // Reserved
+	data = appendUint16(data, len(keyName))
+	data = appendUTF16(data, keyName)
+	data = appendUint16(data, 1) // Rule count
+
+	data = append(data, 0x02, 0x01) // Major and minor version
+	data = appendUint16(data, 0x003e)
+	data = appendUint16(data, TimeZoneRuleFlagRecurCurrent|TimeZoneRuleFlagEffective)
+	data = appendUint16(data, 2007) // Year
+	data = append(data, make([]byte, 14)...)
+	data = appendUint32(data, -60) // Bias
+	data = appendUint32(data, 0)   // Standard bias
+	data = appendUint32(data, -60) // Daylight bias
+	data = appendSystemTime(data, testStandardDate)
+
+	return appendSystemTime(data, testDaylightDate)
+}
+
+func TestGetTimeZoneStruct(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           []byte
+		isErrorPresent bool
+	}{
+		{name: "W. Europe Standard Time", data: getTestTimeZoneStruct()},
+		{name: "truncated", data: getTestTimeZoneStruct()[:47], isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			timeZoneRule, err := GetTimeZoneStruct(test.data)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", timeZoneRule)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if timeZoneRule.Bias != -60 || timeZoneRule.StandardBias != 0 || timeZoneRule.DaylightBias != -60 {
+				t.Fatalf("unexpected biases %+v", timeZoneRule)
+			}
+
+			if timeZoneRule.StandardDate != testStandardDate || timeZoneRule.DaylightDate != testDaylightDate {
+				t.Fatalf("unexpected transition dates %+v and %+v", timeZoneRule.StandardDate, timeZoneRule.DaylightDate)
+			}
+		})
+	}
+}
+
+func TestGetTimeZoneDefinition(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           []byte
+		isErrorPresent bool
+	}{
+		{name: "W. Europe Standard Time", data: getTestTimeZoneDefinition()},
+		{name: "truncated header", data: getTestTimeZoneDefinition()[:20], isErrorPresent: true},
+		{name: "truncated rule", data: getTestTimeZoneDefinition()[:80], isErrorPresent: true},
+		{name: "invalid version", data: append([]byte{0x01}, getTestTimeZoneDefinition()[1:]...), isErrorPresent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			timeZoneDefinition, err := GetTimeZoneDefinition(test.data)
+
+			if test.isErrorPresent {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", timeZoneDefinition)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if timeZoneDefinition.KeyName != "W. Europe Standard Time" || len(timeZoneDefinition.Rules) != 1 {
+				t.Fatalf("unexpected time zone definition %+v", timeZoneDefinition)
+			}
+
+			timeZoneRule := timeZoneDefinition.Rules[0]
+
+			if timeZoneRule.Year != 2007 || timeZoneRule.Bias != -60 || timeZoneRule.DaylightBias != -60 {
+				t.Fatalf("unexpected rule %+v", timeZoneRule)
+			}
+
+			if timeZoneRule.StandardDate != testStandardDate || timeZoneRule.DaylightDate != testDaylightDate {
+				t.Fatalf("unexpected transition dates %+v and %+v", timeZoneRule.StandardDate, timeZoneRule.DaylightDate)
+			}
+		})
+	}
+}